@@ -0,0 +1,30 @@
+package snowflake
+
+import "time"
+
+// maxDefaultTimestamp 是默认位布局下时间戳字段的掩码
+const maxDefaultTimestamp = -1 ^ (-1 << (63 - TimestampShift))
+
+// TimestampOf 返回 ID 生成时的毫秒时间（UTC），按照默认的 Epoch 和位布局解析
+//
+// 只做一次移位、掩码和加法，不分配内存，适合在排序的比较函数中调用。结果与
+// Parse(id).Timestamp 相同。
+func TimestampOf(id int64) time.Time {
+	return time.UnixMilli((id>>TimestampShift)&maxDefaultTimestamp + Epoch).UTC()
+}
+
+// AgeOf 返回 ID 生成至今经过的时间，即 time.Since(TimestampOf(id))
+func AgeOf(id int64) time.Duration {
+	return time.Since(TimestampOf(id))
+}
+
+// TimestampOf 与包级的 TimestampOf 相同，但按照实例的基准时间、时间单位和位布局解析
+func (s *Snowflake) TimestampOf(id int64) time.Time {
+	// 使用无符号移位，WithUnsigned 下时间戳占用最高位时不会符号扩展
+	return s.timeOf(int64(uint64(id)>>s.layout.timestampShift()) & s.maxTimestamp())
+}
+
+// AgeOf 返回 ID 生成至今经过的时间，当前时间读取自实例的时间源
+func (s *Snowflake) AgeOf(id int64) time.Duration {
+	return s.clock.Now().Sub(s.TimestampOf(id))
+}
@@ -0,0 +1,89 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestampOf(t *testing.T) {
+	s, err := NewSnowflake(3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := TimestampOf(id), Parse(id).Timestamp; !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("TimestampOf(%d) = %s, want %s", id, got, want)
+	}
+	if got := TimestampOf(12345<<TimestampShift | 1<<DataCenterShift | MaxSequence); !got.Equal(time.UnixMilli(Epoch + 12345)) {
+		t.Errorf("TimestampOf with all low fields set = %s, want %s", got, time.UnixMilli(Epoch+12345).UTC())
+	}
+	if n := testing.AllocsPerRun(100, func() { TimestampOf(id) }); n != 0 {
+		t.Errorf("TimestampOf allocates %v times, want 0", n)
+	}
+}
+
+func TestSnowflakeTimestampOf(t *testing.T) {
+	epoch := testStart.Add(-time.Hour)
+	for _, tc := range []struct {
+		name    string
+		opts    []Option
+		advance func(s *Snowflake) time.Duration
+		want    func(s *Snowflake) time.Time
+	}{
+		{
+			name:    "unit",
+			opts:    []Option{WithTimeUnit(10 * time.Millisecond)},
+			advance: func(*Snowflake) time.Duration { return 1234567 * time.Microsecond },
+			want:    func(*Snowflake) time.Time { return testStart.Add(1230 * time.Millisecond) },
+		},
+		{
+			// 时间戳字段的最后一个时间单位，无符号布局下 ID 的最高位为 1
+			name:    "unsigned",
+			opts:    []Option{WithUnsigned()},
+			advance: func(s *Snowflake) time.Duration { return s.MaxTime().Sub(testStart) },
+			want:    (*Snowflake).MaxTime,
+		},
+	} {
+		clock := newFakeClock(testStart)
+		s, err := NewSnowflake(1, 1, append(tc.opts, WithClock(clock), WithEpoch(epoch))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(tc.advance(s))
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := s.TimestampOf(id)
+		if want := tc.want(s); !got.Equal(want) || !got.Equal(s.Parse(id).Timestamp) {
+			t.Errorf("%s: TimestampOf(%d) = %s, want %s", tc.name, id, got, want)
+		}
+		if n := testing.AllocsPerRun(100, func() { s.TimestampOf(id) }); n != 0 {
+			t.Errorf("%s: Snowflake.TimestampOf allocates %v times, want 0", tc.name, n)
+		}
+	}
+}
+
+func TestAgeOf(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(1500 * time.Millisecond)
+	if got := s.AgeOf(id); got != 1500*time.Millisecond {
+		t.Errorf("AgeOf = %s, want 1.5s", got)
+	}
+
+	id = int64(MinIDForTime(time.Now().Add(-time.Hour)))
+	if got := AgeOf(id); got < time.Hour || got > time.Hour+time.Minute {
+		t.Errorf("AgeOf of an ID from an hour ago = %s", got)
+	}
+}
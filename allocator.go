@@ -0,0 +1,33 @@
+package snowflake
+
+import "fmt"
+
+// IDAllocator 从外部协调服务（例如 etcd、Redis）租用机器 ID
+//
+// 包本身不依赖任何协调服务，调用方实现该接口后通过 NewSnowflakeWithAllocator 接入。
+// etcdallocator 和 redisallocator 子包同时分配机器 ID 和数据中心 ID，没有实现该接口，
+// 应当把它们返回的两个 ID 直接传给 NewSnowflake。
+type IDAllocator interface {
+	// Acquire 租用一个在集群内唯一的机器 ID
+	Acquire() (int64, error)
+	// Release 归还 Acquire 租用的机器 ID
+	Release() error
+}
+
+// NewSnowflakeWithAllocator 创建一个机器 ID 由 alloc 分配的 Snowflake 实例
+//
+// 创建时调用 alloc.Acquire 取得机器 ID，实例的 Close 调用 alloc.Release 归还。机器 ID
+// 超出位布局的范围或其他参数非法导致创建失败时，已经租用的 ID 会立即归还。
+func NewSnowflakeWithAllocator(dataCenterID int64, alloc IDAllocator, opts ...Option) (*Snowflake, error) {
+	machineID, err := alloc.Acquire()
+	if err != nil {
+		return nil, fmt.Errorf("acquire machine ID: %w", err)
+	}
+	s, err := NewSnowflake(machineID, dataCenterID, opts...)
+	if err != nil {
+		alloc.Release()
+		return nil, err
+	}
+	s.allocator = alloc
+	return s, nil
+}
@@ -0,0 +1,74 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeAllocator 记录 Acquire 和 Release 的调用次数
+type fakeAllocator struct {
+	id         int64
+	acquireErr error
+	releaseErr error
+	acquired   int
+	released   int
+}
+
+func (a *fakeAllocator) Acquire() (int64, error) {
+	a.acquired++
+	return a.id, a.acquireErr
+}
+
+func (a *fakeAllocator) Release() error {
+	a.released++
+	return a.releaseErr
+}
+
+func TestNewSnowflakeWithAllocator(t *testing.T) {
+	alloc := &fakeAllocator{id: 9}
+	s, err := NewSnowflakeWithAllocator(2, alloc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := Parse(id); c.MachineID != 9 || c.DataCenterID != 2 {
+		t.Errorf("node = (%d, %d), want (9, 2)", c.MachineID, c.DataCenterID)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if alloc.acquired != 1 || alloc.released != 1 {
+		t.Errorf("acquired %d, released %d times; want 1 and 1", alloc.acquired, alloc.released)
+	}
+}
+
+func TestNewSnowflakeWithAllocatorErrors(t *testing.T) {
+	errAcquire := errors.New("coordinator unavailable")
+	if _, err := NewSnowflakeWithAllocator(1, &fakeAllocator{acquireErr: errAcquire}); !errors.Is(err, errAcquire) {
+		t.Errorf("Acquire failure: err = %v, want %v", err, errAcquire)
+	}
+
+	// 分配的机器 ID 超出范围时必须立即归还
+	alloc := &fakeAllocator{id: MaxMachineID + 1}
+	if _, err := NewSnowflakeWithAllocator(1, alloc); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("out-of-range machine ID: err = %v, want ErrMachineIDRange", err)
+	}
+	if alloc.released != 1 {
+		t.Errorf("out-of-range machine ID released %d times, want 1", alloc.released)
+	}
+
+	errRelease := errors.New("lease already expired")
+	s, err := NewSnowflakeWithAllocator(1, &fakeAllocator{id: 1, releaseErr: errRelease})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); !errors.Is(err, errRelease) {
+		t.Errorf("Close: err = %v, want %v", err, errRelease)
+	}
+}
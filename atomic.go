@@ -0,0 +1,109 @@
+package snowflake
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// WithLockFree 使用基于 CAS 的无锁实现代替互斥锁
+//
+// 无锁实现将 lastTimestamp 和 sequence 打包到一个 uint64 中，通过
+// CompareAndSwap 重试更新，在大量 goroutine 并发生成时可以避免锁竞争。当前毫秒的
+// 序列号用尽时按照 WaitStrategy 等待：默认的 WaitSleep 休眠到下一毫秒；WaitSpin
+// 先以 runtime.Gosched 让出 CPU 重试，最多 maxAtomicYields 次后同样转为休眠。唯一性
+// 和单调性保证与互斥锁实现相同，所有生成方法都会自动使用无锁实现。
+func WithLockFree() Option {
+	return func(s *Snowflake) {
+		s.lockFree = true
+	}
+}
+
+// maxAtomicYields 是 WaitSpin 策略下无锁实现每次等待下一毫秒时最多让出 CPU 的次数
+const maxAtomicYields = 64
+
+// NewAtomicSnowflake 创建一个使用无锁实现的 Snowflake 实例，等价于附加 WithLockFree 选项
+func NewAtomicSnowflake(machineID int64, dataCenterID int64, opts ...Option) (*Snowflake, error) {
+	return NewSnowflake(machineID, dataCenterID, append(opts, WithLockFree())...)
+}
+
+// nextAtomic 使用 CAS 生成下一个 ID，不需要持有 s.mu，参数含义与 nextLocked 相同
+func (s *Snowflake) nextAtomic(ctx context.Context, block bool) (int64, error) {
+	if s.closed.Load() {
+		return 0, ErrClosed
+	}
+	l := s.layout
+	maxSeq := s.maxSequence()
+	waited := false // 本次调用是否已经通知过观察者在等待下一毫秒
+	yields := 0     // WaitSpin 策略下本次调用已经让出 CPU 的次数
+	for {
+		old := s.packed.Load()
+		last, seq := int64(old>>l.SequenceBits), int64(old)&maxSeq
+		timestamp := s.tick()
+		if err := s.checkTimestamp(timestamp); err != nil {
+			return 0, err
+		}
+
+		switch {
+		case timestamp < last:
+			// 时钟回拨时按策略处理，避免与已经发出的 ID 冲突
+			backwards := last - timestamp
+			s.countClockBackwards()
+			switch {
+			case block && s.rollbackPolicy == RollbackWait && time.Duration(backwards)*s.unit <= s.maxRollbackWait:
+				if err := s.sleepContext(ctx, time.Duration(backwards)*s.unit); err != nil {
+					return 0, err
+				}
+				continue
+			case s.rollbackPolicy == RollbackBorrow && seq < maxSeq && s.withinDrift(backwards):
+				// 借用上次的时间戳，直到序列号即将溢出或回拨超出允许的漂移
+				timestamp, seq = last, seq+1
+			default:
+				return 0, &ClockBackwardsError{Backwards: time.Duration(backwards) * s.unit}
+			}
+		case timestamp == last:
+			if seq >= maxSeq {
+				// 序列号用尽，按照 WaitStrategy 等待下一毫秒
+				if !block {
+					return 0, ErrSequenceExhausted
+				}
+				if err := ctx.Err(); err != nil {
+					return 0, err
+				}
+				if !waited {
+					s.countClockWait()
+					waited = true
+				}
+				if s.waitStrategy == WaitSpin && ctx.Done() == nil && systemSleep(s.clock) && yields < maxAtomicYields {
+					yields++
+					runtime.Gosched()
+					continue
+				}
+				// WaitSleep、让出次数用尽或使用注入的时间源时休眠到下一个时间单位
+				if err := s.sleepContext(ctx, s.untilNextTick()); err != nil {
+					return 0, err
+				}
+				continue
+			}
+			seq++
+		default:
+			seq = 0
+		}
+
+		if s.packed.CompareAndSwap(old, uint64(timestamp)<<l.SequenceBits|uint64(seq)) {
+			s.countGenerated()
+			return s.compose(timestamp, seq), nil
+		}
+	}
+}
+
+// snapshot 返回最后一次生成 ID 时的时间戳和序列号
+func (s *Snowflake) snapshot() (lastTimestamp int64, sequence int64) {
+	if s.lockFree {
+		v := s.packed.Load()
+		return int64(v >> s.layout.SequenceBits), int64(v) & s.layout.maxSequence()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastTimestamp, s.sequence
+}
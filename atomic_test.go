@@ -0,0 +1,127 @@
+package snowflake
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAtomicExhaustedWaitIsBounded(t *testing.T) {
+	for _, w := range []WaitStrategy{WaitSleep, WaitSpin} {
+		// 时钟冻结 20ms，期间序列号已经用尽；统计生成器读取时钟的次数
+		var reads atomic.Int64
+		var released atomic.Bool
+		frozen := testStart.UnixMilli()
+		now := func() int64 {
+			reads.Add(1)
+			if released.Load() {
+				return frozen + 1
+			}
+			return frozen
+		}
+		s, err := NewSnowflake(1, 1, WithLockFree(), WithWaitStrategy(w), WithTimeFunc(now), WithSequenceBits(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := s.Generate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		reads.Store(0)
+		time.AfterFunc(20*time.Millisecond, func() { released.Store(true) })
+		if _, err := s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		// 休眠等待时每毫秒只读取几次时钟，无限制地让出 CPU 会读取成千上万次
+		if n := reads.Load(); n > 1000 {
+			t.Errorf("strategy %d: clock read %d times while waiting, want a bounded number", w, n)
+		}
+	}
+}
+
+func TestAtomicConcurrentUnique(t *testing.T) {
+	s, err := NewAtomicSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const goroutines, perGoroutine = 32, 20000
+	results := make([][]int64, goroutines)
+	var wg sync.WaitGroup
+	for g := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]int64, perGoroutine)
+			for i := range ids {
+				id, err := s.Generate()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if i > 0 && id <= ids[i-1] {
+					t.Errorf("IDs not increasing within a goroutine: %d after %d", id, ids[i-1])
+					return
+				}
+				ids[i] = id
+			}
+			results[g] = ids
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool, goroutines*perGoroutine)
+	for _, ids := range results {
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate ID %d", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func benchmarkParallel(b *testing.B, opts ...Option) {
+	s, err := NewSnowflake(1, 1, opts...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := s.Generate(); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkGenerateParallelMutex(b *testing.B)    { benchmarkParallel(b) }
+func BenchmarkGenerateParallelLockFree(b *testing.B) { benchmarkParallel(b, WithLockFree()) }
+
+func TestAtomicMatchesMutex(t *testing.T) {
+	mutexClock, atomicClock := newFakeClock(testStart), newFakeClock(testStart)
+	m, err := NewSnowflake(3, 4, WithClock(mutexClock), WithSequenceBits(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewAtomicSnowflake(3, 4, WithClock(atomicClock), WithSequenceBits(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 两种实现在相同的时钟下生成相同的 ID 序列，包括跨越序列号用尽的等待
+	for i := 0; i < 50; i++ {
+		want, err := m.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := a.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("ID %d: lock-free %d, mutex %d", i, got, want)
+		}
+	}
+}
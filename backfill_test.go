@@ -0,0 +1,120 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerateAtAfterClose(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GenerateAt(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrClosed) {
+		t.Errorf("GenerateAt after Close: err = %v, want ErrClosed", err)
+	}
+}
+
+func TestGenerateAt(t *testing.T) {
+	epoch := testStart.Add(-24 * time.Hour)
+	s, err := NewSnowflake(7, 3, WithClock(newFakeClock(testStart)), WithEpoch(epoch), WithSequenceBits(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := testStart.Add(-time.Hour + 123456*time.Nanosecond)
+	for seq := int64(0); seq < 4; seq++ {
+		id, err := s.GenerateAt(at)
+		if err != nil {
+			t.Fatalf("GenerateAt %d: %v", seq, err)
+		}
+		// 时间被截断到毫秒，同一时间戳依次分配序列号
+		c := s.Parse(id)
+		if !c.Timestamp.Equal(at.Truncate(time.Millisecond)) || c.Sequence != seq || c.MachineID != 7 || c.DataCenterID != 3 {
+			t.Errorf("GenerateAt %d = %+v, want sequence %d at %s", seq, c, seq, at.Truncate(time.Millisecond))
+		}
+	}
+	if _, err := s.GenerateAt(at); !errors.Is(err, ErrSequenceExhausted) {
+		t.Errorf("GenerateAt with an exhausted sequence: err = %v, want ErrSequenceExhausted", err)
+	}
+	// 其他时间戳的序列号单独记录
+	if id, err := s.GenerateAt(at.Add(time.Millisecond)); err != nil || s.Parse(id).Sequence != 0 {
+		t.Errorf("GenerateAt in another millisecond = %d, %v, want sequence 0", id, err)
+	}
+	if id, err := s.GenerateAt(epoch); err != nil || !s.Parse(id).Timestamp.Equal(epoch) {
+		t.Errorf("GenerateAt(epoch) = %d, %v, want the first timestamp", id, err)
+	}
+}
+
+func TestGenerateAtTimeUnit(t *testing.T) {
+	epoch := testStart.Add(-24 * time.Hour)
+	s, err := NewSnowflake(1, 1, WithClock(newFakeClock(testStart)), WithEpoch(epoch), WithTimeUnit(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := epoch.Add(time.Hour + 17*time.Millisecond)
+	id, err := s.GenerateAt(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Parse(id).Timestamp, epoch.Add(time.Hour+10*time.Millisecond); !got.Equal(want) {
+		t.Errorf("timestamp = %s, want %s truncated to the 10ms unit", got, want)
+	}
+}
+
+func TestGenerateAtOutOfRange(t *testing.T) {
+	epoch := testStart.Add(-24 * time.Hour)
+	s, err := NewSnowflake(1, 1, WithClock(newFakeClock(testStart)), WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GenerateAt(epoch.Add(-time.Millisecond)); err == nil || errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("GenerateAt before the epoch: err = %v, want a non-overflow error", err)
+	}
+	if _, err := s.GenerateAt(s.ExhaustionTime()); !errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("GenerateAt(ExhaustionTime()): err = %v, want ErrEpochOverflow", err)
+	}
+}
+
+func TestGenerateAtOverlap(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, at := range []time.Time{testStart, testStart.Add(999 * time.Microsecond), testStart.Add(time.Hour)} {
+		if _, err := s.GenerateAt(at); !errors.Is(err, ErrBackfillOverlap) {
+			t.Errorf("GenerateAt(%s): err = %v, want ErrBackfillOverlap", at, err)
+		}
+	}
+	id, err := s.GenerateAt(testStart.Add(-time.Millisecond))
+	if err != nil {
+		t.Fatalf("GenerateAt in the millisecond before creation: %v", err)
+	}
+	live, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live <= id {
+		t.Errorf("live ID %d is not after the backfilled ID %d", live, id)
+	}
+
+	// 时钟在创建后回拨，实时生成的时间戳早于创建时刻，回填的上限随之降低
+	s, err = NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(-10 * time.Millisecond)
+	if _, err := s.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GenerateAt(testStart.Add(-5 * time.Millisecond)); !errors.Is(err, ErrBackfillOverlap) {
+		t.Errorf("GenerateAt after the live timestamp: err = %v, want ErrBackfillOverlap", err)
+	}
+	if _, err := s.GenerateAt(testStart.Add(-11 * time.Millisecond)); err != nil {
+		t.Errorf("GenerateAt before the live timestamp: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package snowflake
+
+import "fmt"
+
+// base32Alphabet 是 Crockford Base32 字母表，去掉了 I、L、O 和 U，按 ASCII 顺序排列
+const base32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Base32Length 是 Crockford Base32 编码的固定长度，足以表示任意 uint64
+const Base32Length = 13
+
+// base32Index 将字符映射回其数值，-1 表示非法字符
+//
+// 按照 Crockford 规范，解码时不区分大小写，并将 I、L 视为 1，O 视为 0。
+var base32Index = func() (idx [256]int8) {
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(base32Alphabet); i++ {
+		c := base32Alphabet[i]
+		idx[c] = int8(i)
+		if c >= 'A' && c <= 'Z' {
+			idx[c+'a'-'A'] = int8(i)
+		}
+	}
+	for _, c := range "IiLl" {
+		idx[c] = 1
+	}
+	for _, c := range "Oo" {
+		idx[c] = 0
+	}
+	return idx
+}()
+
+// Base32 返回 ID 的固定宽度 Crockford Base32 编码
+//
+// 输出固定为 13 个大写字符，不足时以 '0' 左补齐。由于宽度固定且字母表按 ASCII
+// 顺序排列，两个编码结果的字典序与 ID 的数值大小顺序一致。id 为负数时 panic，
+// WithUnsigned 实例生成的最高位为 1 的 ID 需要使用 ToCrockfordUint64 编码。
+func (id ID) Base32() string {
+	if id < 0 {
+		panic(fmt.Sprintf("snowflake: cannot base32 encode negative ID %d", id))
+	}
+	return ToCrockfordUint64(uint64(id))
+}
+
+// ParseBase32 将 Crockford Base32 字符串解码为 ID
+//
+// 解码不区分大小写，并将 I、L 视为 1，O 视为 0；接受固定宽度或省略前导 '0' 的输入。
+// 遇到非法字符时返回包装了 ErrInvalidCharacter 的错误，结果超出 int64 范围时返回
+// 包装了 ErrOverflow 的错误。
+func ParseBase32(s string) (ID, error) {
+	// 13 个字符共 65 位，int64 只使用低 63 位，最高位字符只能占用 3 位
+	id, err := parseBase32(s, 7)
+	return ID(id), err
+}
+
+// parseBase32 解码 Crockford Base32 字符串，固定宽度时最高位字符的值不能超过 maxFirst
+func parseBase32(s string, maxFirst int8) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("invalid base32 ID: empty string")
+	}
+	if len(s) > Base32Length {
+		return 0, fmt.Errorf("invalid base32 ID %q: longer than %d characters", s, Base32Length)
+	}
+	var id uint64
+	for i := 0; i < len(s); i++ {
+		d := base32Index[s[i]]
+		if d < 0 {
+			return 0, fmt.Errorf("invalid base32 ID %q: %w %q", s, ErrInvalidCharacter, s[i])
+		}
+		if i == 0 && len(s) == Base32Length && d > maxFirst {
+			return 0, fmt.Errorf("invalid base32 ID %q: %w", s, ErrOverflow)
+		}
+		id = id<<5 | uint64(d)
+	}
+	return id, nil
+}
+
+// ToCrockford 返回 id 的固定宽度 Crockford Base32 编码，格式与 ID.Base32 相同
+func ToCrockford(id int64) string {
+	return ID(id).Base32()
+}
+
+// FromCrockford 解码 Crockford Base32 字符串，规则与 ParseBase32 相同
+func FromCrockford(s string) (int64, error) {
+	id, err := ParseBase32(s)
+	return int64(id), err
+}
+
+// ToCrockfordUint64 返回 ID 的 uint64 视图的固定宽度 Crockford Base32 编码
+//
+// 13 个字符足以表示任意 uint64，对非负 ID 的结果与 ToCrockford 完全相同。
+func ToCrockfordUint64(id uint64) string {
+	var buf [Base32Length]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = base32Alphabet[id&31]
+		id >>= 5
+	}
+	return string(buf[:])
+}
+
+// FromCrockfordUint64 解码 ToCrockfordUint64 生成的字符串，结果超出 uint64 范围时返回
+// 包装了 ErrOverflow 的错误，其他规则与 ParseBase32 相同
+func FromCrockfordUint64(s string) (uint64, error) {
+	// uint64 的最高位字符只能占用 4 位
+	return parseBase32(s, 15)
+}
+
+// GenerateBase32 生成唯一的 ID，并以固定宽度的 Crockford Base32 字符串返回
+//
+// 输出按字典序排序的结果与按生成顺序一致，且不包含容易混淆的字符，适合人工读写。
+// 编码的是 ID 的 uint64 视图，WithUnsigned 实例生成的最高位为 1 的 ID 需要使用
+// FromCrockfordUint64 解码。
+func (s *Snowflake) GenerateBase32() (string, error) {
+	id, err := s.Generate()
+	if err != nil {
+		return "", err
+	}
+	return ToCrockfordUint64(uint64(id)), nil
+}
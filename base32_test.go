@@ -0,0 +1,74 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBase32Vectors(t *testing.T) {
+	for _, v := range []struct {
+		id  ID
+		enc string
+	}{
+		{0, "0000000000000"},
+		{31, "000000000000Z"},
+		{32, "0000000000010"},
+		{1234567890123456789, "128GGYHYYK08N"},
+		{math.MaxInt64, "7ZZZZZZZZZZZZ"},
+	} {
+		if got := v.id.Base32(); got != v.enc {
+			t.Errorf("ID(%d).Base32() = %q, want %q", v.id, got, v.enc)
+		}
+		if id, err := ParseBase32(v.enc); err != nil || id != v.id {
+			t.Errorf("ParseBase32(%q) = %d, %v, want %d", v.enc, id, err, v.id)
+		}
+	}
+}
+
+func TestParseBase32(t *testing.T) {
+	// 不区分大小写，I、L 视为 1，O 视为 0，可以省略前导 0
+	for in, want := range map[string]ID{"z": 31, "1O": 32, "iO": 32, "Lo": 32, "128ggyhyyk08n": 1234567890123456789} {
+		if id, err := ParseBase32(in); err != nil || id != want {
+			t.Errorf("ParseBase32(%q) = %d, %v, want %d", in, id, err, want)
+		}
+	}
+	for _, tc := range []struct {
+		in   string
+		want error
+	}{
+		{"8000000000000", ErrOverflow},
+		{"000000000000U", ErrInvalidCharacter},
+	} {
+		if _, err := ParseBase32(tc.in); !errors.Is(err, tc.want) {
+			t.Errorf("ParseBase32(%q): err = %v, want %v", tc.in, err, tc.want)
+		}
+	}
+	for _, in := range []string{"", "00000000000000"} {
+		if _, err := ParseBase32(in); err == nil {
+			t.Errorf("ParseBase32(%q): err = nil, want an error", in)
+		}
+	}
+}
+
+func TestGenerateBase32(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := ""
+	for i := 0; i < 1000; i++ {
+		enc, err := s.GenerateBase32()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(enc) != Base32Length || enc <= prev {
+			t.Fatalf("GenerateBase32 %d = %q after %q, want fixed width and increasing", i, enc, prev)
+		}
+		id, err := FromCrockford(enc)
+		if err != nil || ToCrockford(id) != enc {
+			t.Fatalf("FromCrockford(%q) = %d, %v, does not round-trip", enc, id, err)
+		}
+		prev = enc
+	}
+}
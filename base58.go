@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"fmt"
+	"math"
+)
+
+// base58Alphabet 是 Bitcoin 使用的 base58 字母表，去掉了容易混淆的 0、O、I 和 l
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Index 将字符映射回其在字母表中的位置，-1 表示非法字符
+var base58Index = func() (idx [256]int8) {
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		idx[base58Alphabet[i]] = int8(i)
+	}
+	return idx
+}()
+
+// Base58 返回 ID 的 base58 编码（Bitcoin 字母表）
+//
+// 输出为规范形式，不带前导填充：0 编码为 "1"，其余值的首字符不会是 '1'。
+// id 为负数时 panic。
+func (id ID) Base58() string {
+	if id < 0 {
+		panic(fmt.Sprintf("snowflake: cannot base58 encode negative ID %d", id))
+	}
+	if id == 0 {
+		return base58Alphabet[:1]
+	}
+	var buf [11]byte
+	i := len(buf)
+	for v := int64(id); v > 0; v /= 58 {
+		i--
+		buf[i] = base58Alphabet[v%58]
+	}
+	return string(buf[i:])
+}
+
+// ParseBase58 将 base58 字符串（Bitcoin 字母表）解码为 ID
+//
+// 遇到字母表以外的字符时返回包装了 ErrInvalidCharacter 的错误，结果超出 int64
+// 范围时返回包装了 ErrOverflow 的错误。
+func ParseBase58(s string) (ID, error) {
+	if s == "" {
+		return 0, fmt.Errorf("invalid base58 ID: empty string")
+	}
+	var id int64
+	for i := 0; i < len(s); i++ {
+		d := base58Index[s[i]]
+		if d < 0 {
+			return 0, fmt.Errorf("invalid base58 ID %q: %w %q", s, ErrInvalidCharacter, s[i])
+		}
+		if id > (math.MaxInt64-int64(d))/58 {
+			return 0, fmt.Errorf("invalid base58 ID %q: %w", s, ErrOverflow)
+		}
+		id = id*58 + int64(d)
+	}
+	return ID(id), nil
+}
@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBase58Vectors(t *testing.T) {
+	for _, v := range []struct {
+		id  ID
+		enc string
+	}{
+		{0, "1"},
+		{57, "z"},
+		{58, "21"},
+		{1234567890123456789, "3sDK21t5nHJ"},
+		{math.MaxInt64, "NQm6nKp8qFC"},
+	} {
+		if got := v.id.Base58(); got != v.enc {
+			t.Errorf("ID(%d).Base58() = %q, want %q", v.id, got, v.enc)
+		}
+		if id, err := ParseBase58(v.enc); err != nil || id != v.id {
+			t.Errorf("ParseBase58(%q) = %d, %v, want %d", v.enc, id, err, v.id)
+		}
+	}
+}
+
+func TestParseBase58Errors(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want error
+	}{
+		{"NQm6nKp8qFD", ErrOverflow},
+		{"0", ErrInvalidCharacter},
+		{"Il", ErrInvalidCharacter},
+	} {
+		if _, err := ParseBase58(tc.in); !errors.Is(err, tc.want) {
+			t.Errorf("ParseBase58(%q): err = %v, want %v", tc.in, err, tc.want)
+		}
+	}
+	if _, err := ParseBase58(""); err == nil {
+		t.Error(`ParseBase58(""): err = nil, want an error`)
+	}
+}
@@ -0,0 +1,121 @@
+package snowflake
+
+import "fmt"
+
+// base62Alphabet 是 base62 编码使用的字母表，按 ASCII 顺序排列
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Base62Length 是 base62 编码的固定长度，足以表示任意 uint64
+const Base62Length = 11
+
+// base62Index 将字符映射回其在字母表中的位置，-1 表示非法字符
+var base62Index = func() (idx [256]int8) {
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(base62Alphabet); i++ {
+		idx[base62Alphabet[i]] = int8(i)
+	}
+	return idx
+}()
+
+// ToBase62 将 ID 编码为使用 [0-9A-Za-z] 字母表的 base62 字符串
+//
+// 输出为固定 11 位宽度，不足时以 '0' 左补齐。由于字母表按 ASCII 顺序排列，
+// 两个编码结果的字典序与其数值大小顺序一致。id 为负数时 panic，负数不是合法的
+// Snowflake ID；WithUnsigned 实例生成的最高位为 1 的 ID 需要使用 ToBase62Uint64 编码。
+func ToBase62(id int64) string {
+	if id < 0 {
+		panic(fmt.Sprintf("snowflake: cannot base62 encode negative ID %d", id))
+	}
+	return ToBase62Uint64(uint64(id))
+}
+
+// ToBase62Uint64 与 ToBase62 相同，但编码 ID 的 uint64 视图
+//
+// 11 位 base62 足以表示任意 uint64，对非负 ID 的结果与 ToBase62 完全相同，因此
+// WithUnsigned 实例生成的 ID 也能保持字典序与生成顺序一致。
+func ToBase62Uint64(id uint64) string {
+	var buf [Base62Length]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = base62Alphabet[id%62]
+		id /= 62
+	}
+	return string(buf[:])
+}
+
+// FromBase62 解码 base62 字符串
+//
+// 接受固定宽度或省略前导 '0' 的输入。遇到字母表以外的字符时返回包装了
+// ErrInvalidCharacter 的错误，结果超出 int64 范围时返回包装了 ErrOverflow 的错误。
+func FromBase62(s string) (int64, error) {
+	id, err := fromBase62(s, maxBase62)
+	return int64(id), err
+}
+
+// FromBase62Uint64 解码 ToBase62Uint64 生成的字符串，结果超出 uint64 范围时返回包装了
+// ErrOverflow 的错误，其他规则与 FromBase62 相同
+func FromBase62Uint64(s string) (uint64, error) {
+	return fromBase62(s, maxBase62Uint64)
+}
+
+// fromBase62 解码 base62 字符串，limit 为允许的最大值的固定宽度编码
+func fromBase62(s, limit string) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("invalid base62 ID: empty string")
+	}
+	if len(s) > Base62Length {
+		return 0, fmt.Errorf("invalid base62 ID %q: longer than %d characters", s, Base62Length)
+	}
+	var id uint64
+	for i := 0; i < len(s); i++ {
+		d := base62Index[s[i]]
+		if d < 0 {
+			return 0, fmt.Errorf("invalid base62 ID %q: %w %q", s, ErrInvalidCharacter, s[i])
+		}
+		id = id*62 + uint64(d)
+	}
+	// 不足 11 位时不可能溢出；满 11 位时按字典序与最大值比较即可判断
+	if len(s) == Base62Length && s > limit {
+		return 0, fmt.Errorf("invalid base62 ID %q: %w", s, ErrOverflow)
+	}
+	return id, nil
+}
+
+// maxBase62 和 maxBase62Uint64 分别是 math.MaxInt64 和 math.MaxUint64 的固定宽度 base62 编码
+var (
+	maxBase62       = ToBase62(1<<63 - 1)
+	maxBase62Uint64 = ToBase62Uint64(1<<64 - 1)
+)
+
+// GenerateString 生成唯一的 ID，并以固定宽度的 base62 字符串返回
+//
+// 编码的是 ID 的 uint64 视图，结果与 ToBase62Uint64 相同：普通实例的 ID 非负，结果
+// 与 ToBase62 一致；WithUnsigned 实例生成的最高位为 1 的 ID 同样可以编码，需要使用
+// FromBase62Uint64 解码。需要十进制字符串时使用 GenerateDecimalString。
+func (s *Snowflake) GenerateString() (string, error) {
+	id, err := s.Generate()
+	if err != nil {
+		return "", err
+	}
+	return ToBase62Uint64(uint64(id)), nil
+}
+
+// GenerateBase62String 与 GenerateString 相同，名称中明确编码方式
+func (s *Snowflake) GenerateBase62String() (string, error) {
+	return s.GenerateString()
+}
+
+// Base62 返回 ID 的固定宽度 base62 编码，格式与 ToBase62 相同
+func (id ID) Base62() string {
+	return ToBase62(int64(id))
+}
+
+// ParseBase62 将 base62 字符串解码为 ID，格式与 FromBase62 相同
+func ParseBase62(s string) (ID, error) {
+	id, err := FromBase62(s)
+	if err != nil {
+		return 0, err
+	}
+	return ID(id), nil
+}
@@ -0,0 +1,135 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"testing"
+)
+
+// base62Vectors 是固定宽度 base62 编码的黄金向量
+var base62Vectors = []struct {
+	id  int64
+	enc string
+}{
+	{0, "00000000000"},
+	{61, "0000000000z"},
+	{62, "00000000010"},
+	{1234567890123456789, "1TCKi1nFuNh"},
+	{math.MaxInt64, "AzL8n0Y58m7"},
+}
+
+func TestToBase62(t *testing.T) {
+	for _, v := range base62Vectors {
+		if got := ToBase62(v.id); got != v.enc {
+			t.Errorf("ToBase62(%d) = %q, want %q", v.id, got, v.enc)
+		}
+	}
+}
+
+func TestToBase62SortOrder(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := make([]string, 1000)
+	for i := range encoded {
+		if encoded[i], err = s.GenerateString(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !sort.StringsAreSorted(encoded) {
+		t.Error("base62 strings of increasing IDs are not sorted")
+	}
+}
+
+func TestToBase62NegativePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ToBase62(-1) did not panic")
+		}
+	}()
+	ToBase62(-1)
+}
+
+func TestParseBase62(t *testing.T) {
+	for _, v := range base62Vectors {
+		id, err := ParseBase62(v.enc)
+		if err != nil || id != ID(v.id) {
+			t.Errorf("ParseBase62(%q) = %d, %v, want %d", v.enc, id, err, v.id)
+		}
+		if got := ID(v.id).Base62(); got != v.enc {
+			t.Errorf("ID(%d).Base62() = %q, want %q", v.id, got, v.enc)
+		}
+	}
+	// 省略前导 0 的输入
+	if id, err := ParseBase62("10"); err != nil || id != 62 {
+		t.Errorf(`ParseBase62("10") = %d, %v, want 62`, id, err)
+	}
+
+	for _, tc := range []struct {
+		in   string
+		want error
+	}{
+		{"AzL8n0Y58m8", ErrOverflow},
+		{"zzzzzzzzzzz", ErrOverflow},
+		{"0000000000-", ErrInvalidCharacter},
+	} {
+		if _, err := ParseBase62(tc.in); !errors.Is(err, tc.want) {
+			t.Errorf("ParseBase62(%q): err = %v, want %v", tc.in, err, tc.want)
+		}
+	}
+	for _, in := range []string{"", "000000000000"} {
+		if _, err := ParseBase62(in); err == nil {
+			t.Errorf("ParseBase62(%q): err = nil, want an error", in)
+		}
+	}
+}
+
+func TestGenerateBase62String(t *testing.T) {
+	s, err := NewSnowflake(7, 3, WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prev string
+	for i := 0; i < 3; i++ {
+		str, err := s.GenerateBase62String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := FromBase62(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(str) != Base62Length || str <= prev || s.Parse(id).Sequence != int64(i) {
+			t.Errorf("GenerateBase62String() = %q after %q, want sequence %d in sort order", str, prev, i)
+		}
+		prev = str
+	}
+
+	s, err = NewSnowflake(7, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(1000, func() {
+		if _, err := s.GenerateBase62String(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 1 {
+		t.Errorf("GenerateBase62String allocates %v times per call, want at most 1", allocs)
+	}
+}
+
+func BenchmarkGenerateBase62String(b *testing.B) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.GenerateBase62String(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
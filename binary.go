@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Bytes 返回 ID 的 8 字节大端序表示
+//
+// 对于非负 ID（Generate 生成的 ID 总是非负的），bytes.Compare 比较两个编码结果的
+// 顺序与 ID 的数值顺序一致，适合作为 BoltDB、Badger 等有序 KV 存储的键做时间范围
+// 扫描。负数的最高位为 1，按字节比较时会排在所有非负 ID 之后。
+func (id ID) Bytes() [8]byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(id))
+	return b
+}
+
+// IDFromBytes 将 8 字节大端序表示解析为 ID，长度不为 8 时返回错误
+func IDFromBytes(b []byte) (ID, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("invalid snowflake ID bytes: want 8 bytes, got %d", len(b))
+	}
+	return ID(binary.BigEndian.Uint64(b)), nil
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler，格式与 Bytes 相同
+func (id ID) MarshalBinary() ([]byte, error) {
+	b := id.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，格式与 Bytes 相同
+func (id *ID) UnmarshalBinary(data []byte) error {
+	v, err := IDFromBytes(data)
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
+
+// Encode 返回 id 的 8 字节大端序表示，格式与 ID.Bytes 相同
+func Encode(id int64) [8]byte {
+	return ID(id).Bytes()
+}
+
+// Decode 解析 Encode 生成的 8 字节大端序表示
+func Decode(b [8]byte) int64 {
+	return int64(binary.BigEndian.Uint64(b[:]))
+}
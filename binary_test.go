@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIDBinary(t *testing.T) {
+	id := ID(0x0102030405060708)
+	data, err := id.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{1, 2, 3, 4, 5, 6, 7, 8}; !bytes.Equal(data, want) {
+		t.Errorf("MarshalBinary() = %v, want %v", data, want)
+	}
+	var got ID
+	if err := got.UnmarshalBinary(data); err != nil || got != id {
+		t.Errorf("UnmarshalBinary(%v) = %d, %v, want %d", data, got, err, id)
+	}
+	for _, n := range []int{0, 7, 9} {
+		if err := got.UnmarshalBinary(make([]byte, n)); err == nil {
+			t.Errorf("UnmarshalBinary of %d bytes: err = nil, want an error", n)
+		}
+	}
+}
+
+func TestIDBytesOrder(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, err := s.GenerateID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		id, err := s.GenerateID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, b := prev.Bytes(), id.Bytes()
+		if bytes.Compare(a[:], b[:]) >= 0 {
+			t.Fatalf("Bytes of %d do not sort before %d", prev, id)
+		}
+		prev = id
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, id := range []int64{0, 1, 1234567890123456789, 1<<63 - 1} {
+		b := Encode(id)
+		if got := Decode(b); got != id {
+			t.Errorf("Decode(Encode(%d)) = %d", id, got)
+		}
+		if b != ID(id).Bytes() {
+			t.Errorf("Encode(%d) = %v, want the same bytes as ID.Bytes", id, b)
+		}
+	}
+	if got := Encode(0x0102030405060708); got != [8]byte{1, 2, 3, 4, 5, 6, 7, 8} {
+		t.Errorf("Encode is not big-endian: %v", got)
+	}
+}
@@ -0,0 +1,119 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MinIDForTime 返回时间 t 所在毫秒内可能生成的最小 ID
+//
+// 数据中心 ID、机器 ID 和序列号均取 0，因此 t 之前生成的所有 ID 都严格小于返回值，
+// 适合用作范围查询的边界，例如 WHERE id < MinIDForTime(cutoff)。t 早于 Epoch 时
+// 返回 0；t 超出时间戳字段能表示的范围时返回 math.MaxInt64。
+func MinIDForTime(t time.Time) int64 {
+	min, _ := defaultLayout.bounds(t, Epoch, time.Millisecond, defaultLayout.maxTimestamp())
+	return min
+}
+
+// MaxIDForTime 返回时间 t 所在毫秒内可能生成的最大 ID
+//
+// 数据中心 ID、机器 ID 和序列号均取最大值，因此 t 所在毫秒及之前生成的所有 ID 都
+// 小于等于返回值。t 早于 Epoch 时返回 0；t 超出时间戳字段能表示的范围时返回
+// math.MaxInt64。
+func MaxIDForTime(t time.Time) int64 {
+	_, max := defaultLayout.bounds(t, Epoch, time.Millisecond, defaultLayout.maxTimestamp())
+	return max
+}
+
+// MinIDForTime 与包级的 MinIDForTime 相同，但按照实例的基准时间、时间单位和位布局计算
+func (s *Snowflake) MinIDForTime(t time.Time) int64 {
+	min, _ := s.layout.bounds(t, s.epoch, s.unit, s.maxTimestamp())
+	return min
+}
+
+// MaxIDForTime 与包级的 MaxIDForTime 相同，但按照实例的基准时间、时间单位和位布局计算
+func (s *Snowflake) MaxIDForTime(t time.Time) int64 {
+	_, max := s.layout.bounds(t, s.epoch, s.unit, s.maxTimestamp())
+	return max
+}
+
+// bounds 返回时间 t 所在时间单位内可能生成的最小和最大 ID，超出范围时按照 idRange
+// 的错误截断为 0 或 math.MaxInt64
+func (l Layout) bounds(t time.Time, epoch int64, unit time.Duration, maxTimestamp int64) (min, max int64) {
+	min, max, err := l.idRange(t, epoch, unit, maxTimestamp)
+	if errors.Is(err, ErrEpochOverflow) {
+		return math.MaxInt64, math.MaxInt64
+	}
+	return min, max
+}
+
+// MaxTime 返回实例的时间戳字段能表示的最后一个时刻，即最后一个时间单位的起点（UTC）
+//
+// 时钟越过该时刻所在的时间单位后，Generate 返回包装了 ErrEpochOverflow 的错误，而不是
+// 生成溢出到符号位的 ID。可以在启动时检查剩余的可用年限。
+func (s *Snowflake) MaxTime() time.Time {
+	return s.timeOf(s.maxTimestamp())
+}
+
+// ExhaustionTime 返回实例的时间戳字段耗尽的时刻，从该时刻起 Generate 返回
+// ErrEpochOverflow
+//
+// 由基准时间、时间单位和时间戳位宽决定，默认配置下为 Epoch 之后 2^41 毫秒，约 69.7 年。
+func (s *Snowflake) ExhaustionTime() time.Time {
+	return s.timeOf(s.maxTimestamp() + 1)
+}
+
+// RemainingLifetime 返回从实例时间源的当前时间到 ExhaustionTime 的剩余时长
+func (s *Snowflake) RemainingLifetime() time.Duration {
+	return s.ExhaustionTime().Sub(s.clock.Now())
+}
+
+// FirstIDForTime 返回时间 t 所在毫秒内可能生成的第一个 ID，即时间戳之外的位全部为 0
+//
+// 与 LastIDForTime 配合可以把时间范围查询转换为 ID 列上的 BETWEEN：
+//
+//	WHERE id BETWEEN FirstIDForTime(t1) AND LastIDForTime(t2)
+//
+// 与 MinIDForTime 不同，t 早于 Epoch 或超出时间戳字段的范围时返回错误而不是截断。
+func FirstIDForTime(t time.Time) (int64, error) {
+	first, _, err := defaultLayout.idRange(t, Epoch, time.Millisecond, defaultLayout.maxTimestamp())
+	return first, err
+}
+
+// LastIDForTime 返回时间 t 所在毫秒内可能生成的最后一个 ID，即时间戳之外的位全部为 1
+//
+// 错误的条件与 FirstIDForTime 相同。
+func LastIDForTime(t time.Time) (int64, error) {
+	_, last, err := defaultLayout.idRange(t, Epoch, time.Millisecond, defaultLayout.maxTimestamp())
+	return last, err
+}
+
+// FirstIDForTime 与包级的 FirstIDForTime 相同，但按照实例的基准时间、时间单位和位布局计算
+func (s *Snowflake) FirstIDForTime(t time.Time) (int64, error) {
+	first, _, err := s.layout.idRange(t, s.epoch, s.unit, s.maxTimestamp())
+	return first, err
+}
+
+// LastIDForTime 与包级的 LastIDForTime 相同，但按照实例的基准时间、时间单位和位布局计算
+func (s *Snowflake) LastIDForTime(t time.Time) (int64, error) {
+	_, last, err := s.layout.idRange(t, s.epoch, s.unit, s.maxTimestamp())
+	return last, err
+}
+
+// idRange 返回时间 t 所在时间单位内第一个和最后一个可能的 ID，t 超出
+// [epoch, maxTimestamp] 的范围时返回错误
+func (l Layout) idRange(t time.Time, epoch int64, unit time.Duration, maxTimestamp int64) (first, last int64, err error) {
+	elapsed := t.UnixNano() - epoch*int64(time.Millisecond)
+	if elapsed < 0 {
+		return 0, 0, fmt.Errorf("time %s is before the epoch %s", t.UTC().Format(time.RFC3339Nano), time.UnixMilli(epoch).UTC().Format(time.RFC3339Nano))
+	}
+	timestamp := elapsed / int64(unit)
+	if timestamp > maxTimestamp {
+		return 0, 0, fmt.Errorf("%w: time %s is beyond the last representable time", ErrEpochOverflow, t.UTC().Format(time.RFC3339Nano))
+	}
+	shift := l.timestampShift()
+	first = timestamp << shift
+	return first, first | (1<<shift - 1), nil
+}
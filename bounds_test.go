@@ -0,0 +1,164 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIDForTimeBounds(t *testing.T) {
+	at := time.UnixMilli(Epoch + 12345)
+	min, max := MinIDForTime(at), MaxIDForTime(at)
+	first, err := FirstIDForTime(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := LastIDForTime(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min != 12345<<22 || max != min|(1<<22-1) {
+		t.Errorf("MinIDForTime, MaxIDForTime = %d, %d, want %d, %d", min, max, 12345<<22, 12345<<22|(1<<22-1))
+	}
+	if first != min || last != max {
+		t.Errorf("FirstIDForTime, LastIDForTime = %d, %d, want %d, %d", first, last, min, max)
+	}
+}
+
+func TestIDForTimeOutOfRange(t *testing.T) {
+	before := time.UnixMilli(Epoch - 1)
+	if min, max := MinIDForTime(before), MaxIDForTime(before); min != 0 || max != 0 {
+		t.Errorf("before epoch: MinIDForTime, MaxIDForTime = %d, %d, want 0, 0", min, max)
+	}
+	if _, err := FirstIDForTime(before); err == nil || errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("FirstIDForTime before epoch: err = %v, want a non-overflow error", err)
+	}
+
+	beyond := time.UnixMilli(Epoch).Add(time.Duration(defaultLayout.maxTimestamp()+1) * time.Millisecond)
+	if min, max := MinIDForTime(beyond), MaxIDForTime(beyond); min != math.MaxInt64 || max != math.MaxInt64 {
+		t.Errorf("beyond range: MinIDForTime, MaxIDForTime = %d, %d, want math.MaxInt64", min, max)
+	}
+	if _, err := LastIDForTime(beyond); !errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("LastIDForTime beyond range: err = %v, want ErrEpochOverflow", err)
+	}
+}
+
+func TestMinMaxIDForTimeContainsGenerated(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(MaxMachineID, MaxDataCenterID, WithClock(clock), WithTimeUnit(10*time.Millisecond),
+		WithEpoch(testStart.Add(-time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(10 * time.Millisecond)
+	cutoff := clock.Now()
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	min, max := s.MinIDForTime(cutoff), s.MaxIDForTime(cutoff)
+	if id < min || id > max {
+		t.Errorf("ID %d generated at %s outside [%d, %d]", id, cutoff, min, max)
+	}
+	if before >= min {
+		t.Errorf("ID %d generated before %s, want less than %d", before, cutoff, min)
+	}
+	// 同一时间单位内的任意时刻得到相同的边界
+	if got := s.MinIDForTime(cutoff.Add(9 * time.Millisecond)); got != min {
+		t.Errorf("MinIDForTime within the same unit = %d, want %d", got, min)
+	}
+}
+
+func TestMaxTime(t *testing.T) {
+	epoch := testStart.Add(-time.Hour)
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want time.Time
+	}{
+		{"default", nil, time.UnixMilli(Epoch + 1<<41 - 1).UTC()},
+		{"epoch", []Option{WithEpoch(epoch)}, epoch.Add((1<<41 - 1) * time.Millisecond)},
+		{"unit", []Option{WithEpoch(epoch), WithTimeUnit(10 * time.Millisecond)}, time.UnixMilli(epoch.UnixMilli() + (1<<41-1)*10).UTC()},
+		{"unsigned", []Option{WithEpoch(epoch), WithUnsigned()}, epoch.Add((1<<42 - 1) * time.Millisecond)},
+	} {
+		s, err := NewSnowflake(1, 1, tc.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.MaxTime(); !got.Equal(tc.want) || got.Location() != time.UTC {
+			t.Errorf("%s: MaxTime() = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMaxTimeIsLastGeneratable(t *testing.T) {
+	epoch := testStart.Add(-time.Hour)
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(s.MaxTime().Sub(clock.Now()))
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatalf("Generate at MaxTime: %v", err)
+	}
+	if got := s.Parse(id).Timestamp; !got.Equal(s.MaxTime()) {
+		t.Errorf("time of the last ID = %s, want %s", got, s.MaxTime())
+	}
+	clock.Advance(time.Millisecond)
+	if _, err := s.Generate(); !errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("Generate after MaxTime: err = %v, want ErrEpochOverflow", err)
+	}
+}
+
+func TestExhaustionTime(t *testing.T) {
+	epoch := testStart.Add(-time.Hour)
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := epoch.Add((1 << 41) * time.Millisecond)
+	if got := s.ExhaustionTime(); !got.Equal(want) {
+		t.Errorf("ExhaustionTime() = %s, want %s", got, want)
+	}
+	if got := s.ExhaustionTime().Sub(s.MaxTime()); got != time.Millisecond {
+		t.Errorf("ExhaustionTime - MaxTime = %s, want one time unit", got)
+	}
+	if got := s.RemainingLifetime(); got != want.Sub(testStart) {
+		t.Errorf("RemainingLifetime() = %s, want %s", got, want.Sub(testStart))
+	}
+
+	clock.Advance(s.RemainingLifetime())
+	if got := s.RemainingLifetime(); got != 0 {
+		t.Errorf("RemainingLifetime() at ExhaustionTime = %s, want 0", got)
+	}
+	if _, err := s.Generate(); !errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("Generate at ExhaustionTime: err = %v, want ErrEpochOverflow", err)
+	}
+	clock.Advance(time.Second)
+	if got := s.RemainingLifetime(); got != -time.Second {
+		t.Errorf("RemainingLifetime() after ExhaustionTime = %s, want -1s", got)
+	}
+}
+
+func TestExhaustionTimeDefault(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.UnixMilli(Epoch + 1<<41)
+	if got := s.ExhaustionTime(); !got.Equal(want) {
+		t.Errorf("ExhaustionTime() = %s, want %s", got, want.UTC())
+	}
+	before := time.Until(want)
+	if got := s.RemainingLifetime(); got <= 0 || got > before {
+		t.Errorf("RemainingLifetime() = %s, want at most %s", got, before)
+	}
+}
@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BufferedSnowflake 在后台预先生成 ID 并缓存在 channel 中，调用方直接取用即可
+//
+// 对延迟敏感的请求路径可以避免在调用时竞争 Snowflake 的锁。注意缓冲区中的 ID
+// 是提前生成的，其嵌入的时间戳可能早于取用的时间。
+type BufferedSnowflake struct {
+	sf     *Snowflake
+	ch     chan bufferedResult
+	done   chan struct{}
+	cancel context.CancelFunc
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// bufferedResult 是生产者发送给消费者的一次生成结果
+type bufferedResult struct {
+	id  int64
+	err error
+}
+
+// NewBufferedSnowflake 创建一个缓冲区大小为 size 的 BufferedSnowflake 并启动生产者
+//
+// 使用完毕后必须调用 Close 停止后台的生产者 goroutine。
+func NewBufferedSnowflake(sf *Snowflake, size int) (*BufferedSnowflake, error) {
+	if sf == nil {
+		return nil, fmt.Errorf("snowflake generator must not be nil")
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("buffer size must be positive, got %d", size)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &BufferedSnowflake{
+		sf:     sf,
+		ch:     make(chan bufferedResult, size),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	b.wg.Add(1)
+	go b.produce(ctx)
+	return b, nil
+}
+
+// produce 持续生成 ID 填充缓冲区，缓冲区满时阻塞而不是空转
+//
+// 生成错误同样会放入缓冲区，由消费者通过 Next 获得。
+func (b *BufferedSnowflake) produce(ctx context.Context) {
+	defer b.wg.Done()
+	for {
+		id, err := b.sf.GenerateContext(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case b.ch <- bufferedResult{id: id, err: err}:
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// Next 从缓冲区取出一个 ID
+//
+// 缓冲区为空时阻塞等待，ctx 被取消时返回 ctx.Err()，生成器关闭后返回 ErrClosed。
+// 生产者遇到的生成错误会原样返回。
+func (b *BufferedSnowflake) Next(ctx context.Context) (int64, error) {
+	select {
+	case <-b.done:
+		return 0, ErrClosed
+	default:
+	}
+	select {
+	case r := <-b.ch:
+		return r.id, r.err
+	case <-b.done:
+		return 0, ErrClosed
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// Close 停止后台的生产者并唤醒所有阻塞在 Next 上的调用方，可以安全地多次调用
+func (b *BufferedSnowflake) Close() error {
+	b.once.Do(func() {
+		close(b.done)
+		b.cancel()
+		b.wg.Wait()
+	})
+	return nil
+}
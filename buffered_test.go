@@ -0,0 +1,110 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBufferedNext(t *testing.T) {
+	sf, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBufferedSnowflake(sf, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	var last int64
+	for i := 0; i < 100; i++ {
+		id, err := b.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("ID %d = %d, not greater than previous %d", i, id, last)
+		}
+		last = id
+	}
+
+	if _, err := NewBufferedSnowflake(nil, 1); err == nil {
+		t.Error("nil generator: err = nil, want an error")
+	}
+	if _, err := NewBufferedSnowflake(sf, 0); err == nil {
+		t.Error("zero size: err = nil, want an error")
+	}
+}
+
+// stalledBuffer 返回一个生产者已经停滞的 BufferedSnowflake：时钟停止不前，
+// 缓冲区中的 ID 都被取走后序列号用尽
+func stalledBuffer(t *testing.T) *BufferedSnowflake {
+	t.Helper()
+	frozen := testStart.UnixMilli()
+	sf, err := NewSnowflake(1, 1, WithTimeFunc(func() int64 { return frozen }), WithSequenceBits(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBufferedSnowflake(sf, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := b.Next(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b
+}
+
+func TestBufferedNextContext(t *testing.T) {
+	b := stalledBuffer(t)
+	defer b.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := b.Next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Next on an empty buffer: err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestBufferedCloseWakesConsumers(t *testing.T) {
+	b := stalledBuffer(t)
+	const consumers = 8
+	errs := make(chan error, consumers)
+	var wg sync.WaitGroup
+	for i := 0; i < consumers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := b.Next(context.Background())
+			errs <- err
+		}()
+	}
+	time.Sleep(10 * time.Millisecond) // 让消费者阻塞在 Next 上
+
+	closed := make(chan struct{})
+	go func() {
+		b.Close()
+		wg.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not return or did not wake the blocked consumers")
+	}
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("blocked Next after Close: err = %v, want ErrClosed", err)
+		}
+	}
+	if _, err := b.Next(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Next after Close: err = %v, want ErrClosed", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
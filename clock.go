@@ -0,0 +1,76 @@
+package snowflake
+
+import (
+	"math/bits"
+	"time"
+)
+
+// Clock 是生成器使用的时间源
+//
+// 生成器读取时间戳以及所有等待、休眠操作都通过 Clock 完成，测试中可以注入手动推进
+// 的假时钟，确定性地验证序列号溢出、时钟回拨和基准时间溢出等边界情况。
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+	// Sleep 阻塞 d 时长
+	Sleep(d time.Duration)
+}
+
+// realClock 使用系统时钟
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// funcClock 由返回 Unix 毫秒时间戳的函数构成，休眠使用系统时钟
+type funcClock func() int64
+
+func (f funcClock) Now() time.Time        { return time.UnixMilli(f()) }
+func (f funcClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// WithClock 设置生成器使用的时间源，默认使用系统时钟，传入 nil 时保持默认值
+func WithClock(c Clock) Option {
+	return func(s *Snowflake) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// tick 返回当前时间相对基准时间经过的时间单位数，即当前的时间戳字段值
+func (s *Snowflake) tick() int64 {
+	return (s.clock.Now().UnixNano() - s.epoch*int64(time.Millisecond)) / int64(s.unit)
+}
+
+// untilNextTick 返回距离下一个时间单位的剩余时间
+func (s *Snowflake) untilNextTick() time.Duration {
+	elapsed := time.Duration(s.clock.Now().UnixNano() - s.epoch*int64(time.Millisecond))
+	return s.unit - elapsed%s.unit
+}
+
+// timeOf 将时间戳字段值换算为绝对时间（UTC）
+func (s *Snowflake) timeOf(timestamp int64) time.Time {
+	return unitTime(s.epoch, uint64(timestamp), s.unit)
+}
+
+// unitTime 返回基准时间 epoch（Unix 毫秒）之后 n 个时间单位的时刻（UTC）
+//
+// 乘积使用 128 位计算，时间单位大于 1 毫秒时时间戳字段能覆盖的范围可能超出
+// time.Duration 的约 292 年，直接相乘会溢出。
+func unitTime(epoch int64, n uint64, unit time.Duration) time.Time {
+	hi, lo := bits.Mul64(n, uint64(unit))
+	sec, nsec := bits.Div64(hi, lo, uint64(time.Second))
+	return time.Unix(epoch/1000+int64(sec), epoch%1000*int64(time.Millisecond)+int64(nsec)).UTC()
+}
+
+// WithTimeUnit 设置时间戳的时间单位，默认为 time.Millisecond
+//
+// 更小的时间单位（例如 time.Microsecond）可以让 ID 的时间顺序更精确，但同样的时间戳
+// 位数能覆盖的年限会按比例缩短：41 位微秒时间戳只能覆盖约 25 天，因此通常需要配合
+// 一个足够新的 WithEpoch 或减少其他字段的位宽。序列号在每个时间单位内重置，等待
+// 逻辑也以时间单位为粒度。
+func WithTimeUnit(unit time.Duration) Option {
+	return func(s *Snowflake) {
+		s.unit = unit
+	}
+}
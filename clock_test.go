@@ -0,0 +1,121 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 是手动推进的测试时钟，Sleep 直接把时间向前推进而不真正休眠
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(t time.Time) *fakeClock {
+	return &fakeClock{now: t}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) { c.Advance(d) }
+
+// Advance 把时钟向前推进 d，d 为负数时模拟时钟回拨
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// testStart 是测试使用的固定起始时间，晚于默认的 Epoch
+var testStart = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// generateWithin 在 timeout 内调用 s.Generate，超时视为测试失败，避免等待逻辑出错时
+// 整个测试挂起
+func generateWithin(t *testing.T, s *Snowflake, timeout time.Duration) int64 {
+	t.Helper()
+	type result struct {
+		id  int64
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		id, err := s.Generate()
+		ch <- result{id, err}
+	}()
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("Generate: %v", r.err)
+		}
+		return r.id
+	case <-time.After(timeout):
+		t.Fatalf("Generate did not return within %s", timeout)
+		return 0
+	}
+}
+
+func TestExhaustedSequenceWaitsThroughInjectedClock(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"mutex", nil},
+		{"mutex spin", []Option{WithWaitStrategy(WaitSpin)}},
+		{"lock-free", []Option{WithLockFree()}},
+		{"lock-free spin", []Option{WithLockFree(), WithWaitStrategy(WaitSpin)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock(testStart)
+			opts := append([]Option{WithClock(clock), WithSequenceBits(2)}, tc.opts...)
+			s, err := NewSnowflake(1, 1, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var last int64
+			for i := 0; i < 10; i++ {
+				id := generateWithin(t, s, 2*time.Second)
+				if id <= last {
+					t.Fatalf("ID %d = %d, not greater than previous %d", i, id, last)
+				}
+				last = id
+			}
+			// 每个时间单位 4 个 ID，10 个 ID 需要时钟前进 2 毫秒
+			if got, want := clock.Now(), testStart.Add(2*time.Millisecond); got != want {
+				t.Errorf("clock = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestWithTimeUnit(t *testing.T) {
+	for _, unit := range []time.Duration{time.Microsecond, 10 * time.Millisecond} {
+		clock := newFakeClock(testStart)
+		s, err := NewSnowflake(1, 1, WithClock(clock), WithTimeUnit(unit), WithEpoch(testStart.Add(-time.Hour)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := first>>TimestampShift, int64(time.Hour/unit); got != want {
+			t.Errorf("unit %s: timestamp field = %d, want %d", unit, got, want)
+		}
+		clock.Advance(unit)
+		second, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := s.Parse(second); !c.Timestamp.Equal(testStart.Add(unit)) || c.Sequence != 0 {
+			t.Errorf("unit %s: ID one unit later = %+v, want %s with sequence 0", unit, c, testStart.Add(unit))
+		}
+	}
+	if _, err := NewSnowflake(1, 1, WithTimeUnit(0)); err == nil {
+		t.Error("zero time unit: err = nil, want an error")
+	}
+}
@@ -0,0 +1,203 @@
+// Command snowflake 生成和拆解 Snowflake ID。
+//
+// 用法：
+//
+//	snowflake [flags]                 生成 -n 个 ID
+//	snowflake [flags] decode [id...]  拆解 ID，未给出 ID 时从标准输入逐行读取
+//	snowflake -serve :8080            以 HTTP 服务模式运行
+//
+// 拆解时按照 -format 解析输入，任意一个 ID 解析失败时以非零状态码退出。
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bart-k/snowflake"
+	"github.com/bart-k/snowflake/httpserver"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// config 是解析后的命令行参数
+type config struct {
+	n            int
+	machineID    int64
+	dataCenterID int64
+	epoch        int64
+	format       string
+	serve        string
+	decode       bool
+	args         []string
+}
+
+// parseFlags 解析命令行参数，第一个非 flag 参数为 decode 时进入拆解模式
+func parseFlags(args []string, stderr io.Writer) (config, error) {
+	var cfg config
+	fs := flag.NewFlagSet("snowflake", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.IntVar(&cfg.n, "n", 10, "生成的 ID 数量")
+	fs.Int64Var(&cfg.machineID, "machine", 1, "机器 ID")
+	fs.Int64Var(&cfg.dataCenterID, "datacenter", 1, "数据中心 ID")
+	fs.Int64Var(&cfg.epoch, "epoch", snowflake.Epoch, "基准时间（Unix 毫秒）")
+	fs.StringVar(&cfg.format, "format", "decimal", "ID 的格式：decimal、base62、hex 或 json")
+	fs.StringVar(&cfg.serve, "serve", "", "以 HTTP 服务模式运行并监听该地址，例如 :8080")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	switch cfg.format {
+	case "decimal", "base62", "hex", "json":
+	default:
+		return cfg, fmt.Errorf("unknown format %q", cfg.format)
+	}
+	if cfg.n < 0 {
+		return cfg, fmt.Errorf("count must not be negative, got %d", cfg.n)
+	}
+	if rest := fs.Args(); len(rest) > 0 {
+		if rest[0] != "decode" {
+			return cfg, fmt.Errorf("unknown command %q", rest[0])
+		}
+		cfg.decode = true
+		cfg.args = rest[1:]
+	}
+	return cfg, nil
+}
+
+// run 执行命令并返回进程的退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, err := parseFlags(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	sf, err := snowflake.NewSnowflake(cfg.machineID, cfg.dataCenterID, snowflake.WithEpoch(time.UnixMilli(cfg.epoch)))
+	if err != nil {
+		fmt.Fprintln(stderr, "Error creating snowflake:", err)
+		return 1
+	}
+
+	switch {
+	case cfg.serve != "":
+		fmt.Fprintln(stdout, "Serving on", cfg.serve)
+		if err := http.ListenAndServe(cfg.serve, httpserver.New(sf)); err != nil {
+			fmt.Fprintln(stderr, "Error serving:", err)
+			return 1
+		}
+		return 0
+	case cfg.decode:
+		return decode(sf, cfg, stdin, stdout, stderr)
+	default:
+		return generate(sf, cfg, stdout, stderr)
+	}
+}
+
+// generate 按照 cfg.format 输出 cfg.n 个 ID，json 格式输出一个字符串数组
+func generate(sf *snowflake.Snowflake, cfg config, stdout, stderr io.Writer) int {
+	ids := make([]snowflake.ID, 0, cfg.n)
+	for i := 0; i < cfg.n; i++ {
+		id, err := sf.GenerateID()
+		if err != nil {
+			fmt.Fprintln(stderr, "Error generating ID:", err)
+			return 1
+		}
+		ids = append(ids, id)
+	}
+
+	if cfg.format == "json" {
+		b, err := json.Marshal(ids)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error encoding IDs:", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(b))
+		return 0
+	}
+	for _, id := range ids {
+		fmt.Fprintln(stdout, formatID(id, cfg.format))
+	}
+	return 0
+}
+
+// decode 拆解 cfg.args 中的 ID，未给出时从 stdin 逐行读取，空行会被忽略
+func decode(sf *snowflake.Snowflake, cfg config, stdin io.Reader, stdout, stderr io.Writer) int {
+	code := 0
+	decodeOne := func(s string) {
+		id, err := parseID(s, cfg.format)
+		if err == nil {
+			var c snowflake.Components
+			if c, err = sf.Decompose(int64(id)); err == nil {
+				fmt.Fprintf(stdout, "%s ts=%s dc=%d machine=%d seq=%d\n", s, c.Timestamp.Format(time.RFC3339Nano), c.DataCenterID, c.MachineID, c.Sequence)
+				return
+			}
+		}
+		fmt.Fprintf(stderr, "Error decoding %q: %v\n", s, err)
+		code = 1
+	}
+
+	if len(cfg.args) > 0 {
+		for _, s := range cfg.args {
+			decodeOne(s)
+		}
+		return code
+	}
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		if s := strings.TrimSpace(scanner.Text()); s != "" {
+			decodeOne(s)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(stderr, "Error reading input:", err)
+		return 1
+	}
+	return code
+}
+
+// formatID 按照 format 编码单个 ID
+func formatID(id snowflake.ID, format string) string {
+	switch format {
+	case "base62":
+		return id.Base62()
+	case "hex":
+		return strconv.FormatInt(int64(id), 16)
+	case "json":
+		b, _ := json.Marshal(id)
+		return string(b)
+	default:
+		return id.String()
+	}
+}
+
+// parseID 按照 format 解析单个 ID，json 格式接受带引号的字符串或数字
+func parseID(s string, format string) (snowflake.ID, error) {
+	switch format {
+	case "base62":
+		return snowflake.ParseBase62(s)
+	case "hex":
+		v, err := strconv.ParseInt(s, 16, 64)
+		return snowflake.ID(v), err
+	case "json":
+		var id snowflake.ID
+		err := json.Unmarshal([]byte(s), &id)
+		return id, err
+	default:
+		v, err := strconv.ParseInt(s, 10, 64)
+		return snowflake.ID(v), err
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseFlagsServe(t *testing.T) {
+	cfg, err := parseFlags([]string{"-serve", ":8080", "-machine", "3"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.serve != ":8080" || cfg.machineID != 3 || cfg.decode {
+		t.Errorf("parseFlags = %+v, want serve :8080 with machine 3", cfg)
+	}
+}
+
+// runCmd 执行 run 并返回退出码、标准输出和标准错误
+func runCmd(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr strings.Builder
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestParseFlags(t *testing.T) {
+	for _, tc := range []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"-n", "3", "-format", "hex"}, false},
+		{[]string{"decode", "1", "2"}, false},
+		{[]string{"-format", "octal"}, true},
+		{[]string{"-n", "-1"}, true},
+		{[]string{"-n", "x"}, true},
+		{[]string{"encode"}, true},
+	} {
+		if _, err := parseFlags(tc.args, io.Discard); (err != nil) != tc.wantErr {
+			t.Errorf("parseFlags(%q): err = %v, want error %t", tc.args, err, tc.wantErr)
+		}
+	}
+	cfg, err := parseFlags([]string{"-format", "base62", "decode", "a", "b"}, io.Discard)
+	if err != nil || !cfg.decode || cfg.format != "base62" || len(cfg.args) != 2 {
+		t.Errorf("parseFlags decode = %+v, %v, want base62 decode of 2 IDs", cfg, err)
+	}
+}
+
+func TestRunGenerateAndDecode(t *testing.T) {
+	for _, format := range []string{"decimal", "base62", "hex", "json"} {
+		code, out, errOut := runCmd(t, "", "-n", "3", "-machine", "7", "-datacenter", "2", "-format", format)
+		if code != 0 {
+			t.Fatalf("%s: exit code %d: %s", format, code, errOut)
+		}
+		var ids []string
+		if format == "json" {
+			if err := json.Unmarshal([]byte(out), &ids); err != nil {
+				t.Fatalf("json output %q: %v", out, err)
+			}
+		} else {
+			ids = strings.Fields(out)
+		}
+		if len(ids) != 3 {
+			t.Fatalf("%s: generated %q, want 3 IDs", format, out)
+		}
+
+		// 生成的 ID 按照相同的格式拆解，参数和标准输入两种方式结果相同
+		args := append([]string{"-format", format, "decode"}, ids...)
+		if format == "json" {
+			args = []string{"-format", format, "decode", strconv.Quote(ids[0])}
+		}
+		code, out, errOut = runCmd(t, "", args...)
+		if code != 0 {
+			t.Fatalf("%s decode: exit code %d: %s", format, code, errOut)
+		}
+		if !strings.Contains(out, "dc=2 machine=7 seq=0") {
+			t.Errorf("%s decode output %q, want dc=2 machine=7 seq=0", format, out)
+		}
+		if format != "json" {
+			_, fromStdin, _ := runCmd(t, strings.Join(ids, "\n")+"\n\n", "-format", format, "decode")
+			if fromStdin != out {
+				t.Errorf("%s decode from stdin = %q, want %q", format, fromStdin, out)
+			}
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if code, _, _ := runCmd(t, "", "-format", "octal"); code != 2 {
+		t.Errorf("unknown format: exit code %d, want 2", code)
+	}
+	if code, _, _ := runCmd(t, "", "-machine", "32"); code != 1 {
+		t.Errorf("machine ID out of range: exit code %d, want 1", code)
+	}
+	if code, _, _ := runCmd(t, "", "-h"); code != 0 {
+		t.Errorf("-h: exit code %d, want 0", code)
+	}
+	code, out, errOut := runCmd(t, "", "decode", "123", "abc")
+	if code != 1 || !strings.Contains(out, "123 ") || !strings.Contains(errOut, `"abc"`) {
+		t.Errorf("decode with one invalid ID: exit %d, stdout %q, stderr %q; want 1 with the valid ID decoded", code, out, errOut)
+	}
+}
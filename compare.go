@@ -0,0 +1,22 @@
+package snowflake
+
+import "cmp"
+
+// Compare 比较两个 ID 的先后顺序，a 早于 b 时返回 -1，相同时返回 0，晚于 b 时返回 +1
+//
+// 顺序依次由时间戳、数据中心 ID、机器 ID 和序列号决定。各字段从高位到低位排列，因此
+// 结果与按 uint64 比较原始位相同：对于普通的非负 ID 与 int64 的自然顺序一致，对于
+// WithUnsigned 生成、最高位为 1 的 ID 也能得到正确的时间顺序。
+func Compare(a, b int64) int {
+	return cmp.Compare(uint64(a), uint64(b))
+}
+
+// Before 判断 id 是否早于 other，顺序与 Compare 相同
+func (id ID) Before(other ID) bool {
+	return Compare(int64(id), int64(other)) < 0
+}
+
+// After 判断 id 是否晚于 other，顺序与 Compare 相同
+func (id ID) After(other ID) bool {
+	return Compare(int64(id), int64(other)) > 0
+}
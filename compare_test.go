@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b int64
+		want int
+	}{
+		{1, 2, -1},
+		{2, 1, 1},
+		{5, 5, 0},
+		{0, math.MaxInt64, -1},
+		// 最高位为 1 的无符号 ID 晚于所有非负 ID
+		{math.MinInt64, math.MaxInt64, 1},
+		{-1, math.MinInt64, 1},
+	} {
+		if got := Compare(tc.a, tc.b); got != tc.want {
+			t.Errorf("Compare(%d, %d) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		if got := ID(tc.a).Before(ID(tc.b)); got != (tc.want < 0) {
+			t.Errorf("ID(%d).Before(%d) = %t", tc.a, tc.b, got)
+		}
+		if got := ID(tc.a).After(ID(tc.b)); got != (tc.want > 0) {
+			t.Errorf("ID(%d).After(%d) = %t", tc.a, tc.b, got)
+		}
+	}
+}
+
+func TestCompareUnsignedOrder(t *testing.T) {
+	epoch := testStart.Add(-time.Hour)
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithEpoch(epoch), WithUnsigned())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, at := range []time.Time{testStart, s.MaxTime().Add(-time.Hour), s.MaxTime()} {
+		clock.Advance(at.Sub(clock.Now()))
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if ids[2] >= 0 {
+		t.Fatalf("last ID %d does not use the top bit", ids[2])
+	}
+	if !slices.IsSortedFunc(ids, Compare) {
+		t.Errorf("IDs in time order %v are not sorted by Compare", ids)
+	}
+	if !ID(ids[0]).Before(ID(ids[2])) || !ID(ids[2]).After(ID(ids[1])) {
+		t.Error("Before and After disagree with the generation order")
+	}
+}
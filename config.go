@@ -0,0 +1,156 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Config 汇总创建 Snowflake 实例的所有可调参数，便于从 JSON 或 YAML 配置文件中读取
+//
+// 所有字段的零值都表示使用默认值，因此只需要写出需要修改的部分。时长字段使用
+// time.ParseDuration 的格式，例如 "10ms"。三个位宽字段全部为 0 时使用默认位布局，
+// 只要有一个非 0 就按照给出的值使用，未给出的字段视为 0 位。
+//
+//	machine_id: 3
+//	data_center_id: 1
+//	epoch: 2024-01-01T00:00:00Z
+//	rollback_policy: borrow
+//	max_backward_drift: 5ms
+type Config struct {
+	MachineID    int64     `json:"machine_id" yaml:"machine_id"`         // 机器 ID
+	DataCenterID int64     `json:"data_center_id" yaml:"data_center_id"` // 数据中心 ID
+	Epoch        time.Time `json:"epoch" yaml:"epoch"`                   // 基准时间，零值为 Epoch
+	TimeUnit     string    `json:"time_unit" yaml:"time_unit"`           // 时间戳的时间单位，默认为 1ms
+
+	MachineBits    int `json:"machine_bits" yaml:"machine_bits"`         // 机器 ID 位宽
+	DataCenterBits int `json:"data_center_bits" yaml:"data_center_bits"` // 数据中心 ID 位宽
+	SequenceBits   int `json:"sequence_bits" yaml:"sequence_bits"`       // 序列号位宽
+	TagBits        int `json:"tag_bits" yaml:"tag_bits"`                 // 序列号字段中分配给标签的位数
+
+	// RollbackPolicy 为 error、wait 或 borrow，默认为 error
+	RollbackPolicy   string `json:"rollback_policy" yaml:"rollback_policy"`
+	MaxRollbackWait  string `json:"max_rollback_wait" yaml:"max_rollback_wait"`   // wait 策略的最大等待时间，默认为 5ms
+	MaxBackwardDrift string `json:"max_backward_drift" yaml:"max_backward_drift"` // borrow 策略允许的最大回拨幅度，默认不限制
+
+	LockFree            bool `json:"lock_free" yaml:"lock_free"`                         // 是否使用无锁实现
+	Unsigned            bool `json:"unsigned" yaml:"unsigned"`                           // 是否把符号位也用于时间戳
+	RandomSequenceStart bool `json:"random_sequence_start" yaml:"random_sequence_start"` // 每毫秒是否从随机序列号开始
+
+	StateFile     string `json:"state_file" yaml:"state_file"`         // 状态文件路径，为空时不启用
+	StateInterval string `json:"state_interval" yaml:"state_interval"` // 状态文件写入间隔
+}
+
+// rollbackPolicies 是 Config.RollbackPolicy 可以使用的取值
+var rollbackPolicies = map[string]RollbackPolicy{
+	"":       RollbackError,
+	"error":  RollbackError,
+	"wait":   RollbackWait,
+	"borrow": RollbackBorrow,
+}
+
+// Validate 检查配置是否合法，返回的错误通过 errors.Join 包含所有发现的问题
+func (c Config) Validate() error {
+	_, err := c.options()
+	return err
+}
+
+// NewFromConfig 按照 cfg 创建一个 Snowflake 实例
+//
+// 配置不合法时返回与 Config.Validate 相同的错误。
+func NewFromConfig(cfg Config) (*Snowflake, error) {
+	opts, err := cfg.options()
+	if err != nil {
+		return nil, err
+	}
+	return NewSnowflake(cfg.MachineID, cfg.DataCenterID, opts...)
+}
+
+// options 把配置转换为选项，同时收集所有不合法的字段
+func (c Config) options() ([]Option, error) {
+	var opts []Option
+	var errs []error
+	duration := func(name, v string) time.Duration {
+		if v == "" {
+			return 0
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", name, err))
+		} else if d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", name, d))
+		}
+		return d
+	}
+
+	layout := defaultLayout
+	if c.MachineBits != 0 || c.DataCenterBits != 0 || c.SequenceBits != 0 {
+		layout = Layout{MachineBits: c.MachineBits, DataCenterBits: c.DataCenterBits, SequenceBits: c.SequenceBits}
+		opts = append(opts, WithLayout(layout))
+	}
+	if err := layout.validate(maxLayoutBits); err != nil {
+		errs = append(errs, err)
+	} else {
+		if maxID := layout.maxMachineID(); c.MachineID < 0 || c.MachineID > maxID {
+			errs = append(errs, &RangeError{Err: ErrMachineIDRange, Value: c.MachineID, Max: maxID})
+		}
+		if maxID := layout.maxDataCenterID(); c.DataCenterID < 0 || c.DataCenterID > maxID {
+			errs = append(errs, &RangeError{Err: ErrDataCenterIDRange, Value: c.DataCenterID, Max: maxID})
+		}
+		if c.TagBits < 0 || c.TagBits >= layout.SequenceBits {
+			errs = append(errs, fmt.Errorf("tag bits must be between 0 and %d, got %d", layout.SequenceBits-1, c.TagBits))
+		}
+	}
+	if c.TagBits != 0 {
+		opts = append(opts, WithTagBits(c.TagBits))
+	}
+
+	if !c.Epoch.IsZero() {
+		if c.Epoch.After(time.Now()) {
+			errs = append(errs, fmt.Errorf("epoch %s must not be in the future", c.Epoch.UTC().Format(time.RFC3339Nano)))
+		}
+		opts = append(opts, WithEpoch(c.Epoch))
+	}
+	if unit := duration("time unit", c.TimeUnit); unit > 0 {
+		opts = append(opts, WithTimeUnit(unit))
+	}
+
+	if policy, ok := rollbackPolicies[c.RollbackPolicy]; ok {
+		opts = append(opts, WithRollbackPolicy(policy))
+	} else {
+		errs = append(errs, fmt.Errorf("unknown rollback policy %q, must be error, wait or borrow", c.RollbackPolicy))
+	}
+	if d := duration("max rollback wait", c.MaxRollbackWait); d > 0 {
+		opts = append(opts, WithMaxRollbackWait(d))
+	}
+	if d := duration("max backward drift", c.MaxBackwardDrift); d > 0 {
+		if c.RollbackPolicy != "" && c.RollbackPolicy != "borrow" {
+			errs = append(errs, fmt.Errorf("max backward drift requires the borrow rollback policy, got %q", c.RollbackPolicy))
+		}
+		opts = append(opts, WithMaxBackwardDrift(d))
+	}
+
+	if c.LockFree {
+		if c.RandomSequenceStart {
+			errs = append(errs, errors.New("random sequence start is not supported by the lock-free generator"))
+		}
+		opts = append(opts, WithLockFree())
+	}
+	if c.Unsigned {
+		opts = append(opts, WithUnsigned())
+	}
+	if c.RandomSequenceStart {
+		opts = append(opts, WithRandomSequenceStart())
+	}
+
+	if c.StateFile != "" {
+		opts = append(opts, WithStateFile(c.StateFile))
+	}
+	if d := duration("state interval", c.StateInterval); d > 0 {
+		if c.StateFile == "" {
+			errs = append(errs, errors.New("state interval requires a state file"))
+		}
+		opts = append(opts, WithStateInterval(d))
+	}
+	return opts, errors.Join(errs...)
+}
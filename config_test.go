@@ -0,0 +1,137 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigJSON(t *testing.T) {
+	const doc = `{
+		"machine_id": 3,
+		"data_center_id": 1,
+		"epoch": "2024-01-01T00:00:00Z",
+		"time_unit": "10ms",
+		"tag_bits": 2,
+		"rollback_policy": "borrow",
+		"max_backward_drift": "5ms"
+	}`
+	var cfg Config
+	if err := json.Unmarshal([]byte(doc), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		MachineID:        3,
+		DataCenterID:     1,
+		Epoch:            time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		TimeUnit:         "10ms",
+		TagBits:          2,
+		RollbackPolicy:   "borrow",
+		MaxBackwardDrift: "5ms",
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("decoded config = %+v, want %+v", cfg, want)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	s, err := NewFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.unit != 10*time.Millisecond || s.epoch != want.Epoch.UnixMilli() || s.tagBits != 2 ||
+		s.rollbackPolicy != RollbackBorrow || s.maxBackwardDrift != 5*time.Millisecond {
+		t.Errorf("NewFromConfig did not apply every field: unit %s, epoch %d, tag bits %d, policy %v, drift %s",
+			s.unit, s.epoch, s.tagBits, s.rollbackPolicy, s.maxBackwardDrift)
+	}
+	id, err := s.GenerateTagged(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := s.Parse(id); c.MachineID != 3 || c.DataCenterID != 1 || c.Tag != 3 {
+		t.Errorf("Parse = %+v, want machine 3, data center 1, tag 3", c)
+	}
+}
+
+func TestConfigTags(t *testing.T) {
+	// YAML 和 JSON 使用相同的字段名，配置文件可以在两种格式之间直接转换
+	typ := reflect.TypeOf(Config{})
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		j, y := f.Tag.Get("json"), f.Tag.Get("yaml")
+		if j == "" || j != y {
+			t.Errorf("field %s: json tag %q and yaml tag %q differ", f.Name, j, y)
+		}
+	}
+}
+
+func TestConfigLayout(t *testing.T) {
+	cfg := Config{MachineID: 200, MachineBits: 8, SequenceBits: 14}
+	s, err := NewFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Layout{MachineBits: 8, SequenceBits: 14}); s.layout != want {
+		t.Errorf("layout = %+v, want %+v", s.layout, want)
+	}
+	if s, err := NewFromConfig(Config{}); err != nil || s.layout != defaultLayout {
+		t.Errorf("zero Config: layout = %+v, err = %v, want the default layout", s.layout, err)
+	}
+}
+
+func TestConfigValidateJoinsErrors(t *testing.T) {
+	cfg := Config{
+		MachineID:        32,
+		DataCenterID:     -1,
+		Epoch:            time.Now().Add(time.Hour),
+		TimeUnit:         "soon",
+		RollbackPolicy:   "ignore",
+		MaxRollbackWait:  "-1ms",
+		MaxBackwardDrift: "5ms",
+		LockFree:         true,
+		StateInterval:    "1s",
+	}
+	cfg.RandomSequenceStart = true
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want errors")
+	}
+	if !errors.Is(err, ErrMachineIDRange) || !errors.Is(err, ErrDataCenterIDRange) {
+		t.Errorf("Validate() = %v, want both ID range errors", err)
+	}
+	for _, want := range []string{
+		"must not be in the future",
+		"invalid time unit",
+		`unknown rollback policy "ignore"`,
+		"max rollback wait must be positive",
+		"max backward drift requires the borrow rollback policy",
+		"random sequence start is not supported by the lock-free generator",
+		"state interval requires a state file",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want it to report %q", err, want)
+		}
+	}
+	if got := len(err.(interface{ Unwrap() []error }).Unwrap()); got != 9 {
+		t.Errorf("Validate() joined %d errors, want 9", got)
+	}
+	if _, nerr := NewFromConfig(cfg); nerr == nil || nerr.Error() != err.Error() {
+		t.Errorf("NewFromConfig error = %v, want the Validate error", nerr)
+	}
+}
+
+func TestConfigValidateLayout(t *testing.T) {
+	for _, cfg := range []Config{
+		{MachineBits: 20, SequenceBits: 10},
+		{TagBits: 12},
+		{MachineBits: 4, SequenceBits: 12, MachineID: 16},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", cfg)
+		}
+	}
+}
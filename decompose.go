@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// Components 表示一个 Snowflake ID 拆解后的各个组成部分
+type Components struct {
+	Timestamp    time.Time // ID 生成时的毫秒时间（UTC）
+	DataCenterID int64     // 数据中心 ID
+	MachineID    int64     // 机器 ID
+	Sequence     int64     // 毫秒内序列号
+	Tag          int64     // 序列号字段高位携带的标签，只有启用 WithTagBits 的实例拆解时才会设置
+}
+
+// Decompose 将 ID 拆解为时间戳、数据中心 ID、机器 ID 和序列号
+//
+// 拆解使用与 Generate 相同的 Epoch 和位偏移，因此对 Generate 的输出进行
+// Decompose 可以得到生成时的输入和毫秒时间。负数 ID 不可能由 Generate 产生，
+// 此时返回错误。
+func Decompose(id int64) (Components, error) {
+	if id < 0 {
+		return Components{}, fmt.Errorf("invalid snowflake ID %d: must not be negative", id)
+	}
+	return Parse(id), nil
+}
+
+// Parse 按固定的位布局对 ID 做逆向位移和掩码，拆解出各个组成部分
+//
+// 由于位布局是固定的，Parse 适用于任意 Snowflake 实例生成的 ID。与 Decompose
+// 不同，Parse 不做任何校验，调用方需要自行保证 ID 合法。
+func Parse(id int64) Components {
+	return defaultLayout.parse(id, Epoch, time.Millisecond)
+}
+
+// Decompose 按照实例的基准时间和位布局拆解 ID
+//
+// 使用 WithEpoch 或自定义位布局创建的实例，必须通过该方法拆解自己生成的 ID，
+// 包级的 Decompose 和 ID 的方法只适用于默认配置。
+func (s *Snowflake) Decompose(id int64) (Components, error) {
+	if id < 0 {
+		return Components{}, fmt.Errorf("invalid snowflake ID %d: must not be negative", id)
+	}
+	return s.Parse(id), nil
+}
+
+// Parse 按照实例的基准时间和位布局拆解 ID，不做任何校验
+func (s *Snowflake) Parse(id int64) Components {
+	if s.sonyflake {
+		return parseSonyflake(uint64(id), s.epoch, s.unit)
+	}
+	return s.splitTag(s.layout.parse(id, s.epoch, s.unit))
+}
+
+// Describe 返回便于阅读的 ID 拆解结果，适合输出到日志
+//
+// 输出形如：
+//
+//	ts=2024-01-02T03:04:05.678Z unix_ms=1704164645678 epoch_ms=75000000 dc=3 machine=7 seq=123
+//
+// 其中 unix_ms 是绝对的 Unix 毫秒时间戳，epoch_ms 是相对 Epoch 的毫秒偏移。
+func Describe(id int64) string {
+	c := Parse(id)
+	return fmt.Sprintf("ts=%s unix_ms=%d epoch_ms=%d dc=%d machine=%d seq=%d",
+		c.Timestamp.Format("2006-01-02T15:04:05.000Z07:00"), c.Timestamp.UnixMilli(), id>>TimestampShift,
+		c.DataCenterID, c.MachineID, c.Sequence)
+}
@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecompose(t *testing.T) {
+	s, err := NewSnowflake(7, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Decompose(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.MachineID != 7 || c.DataCenterID != 3 || c.Sequence != 0 {
+		t.Errorf("Decompose(%d) = %+v, want machine 7, data center 3, sequence 0", id, c)
+	}
+	if c.Timestamp.Before(before) || c.Timestamp.After(time.Now()) {
+		t.Errorf("Decompose(%d).Timestamp = %s, want between %s and now", id, c.Timestamp, before)
+	}
+
+	if _, err := Decompose(-1); err == nil {
+		t.Error("Decompose(-1): err = nil, want an error")
+	}
+}
+
+func TestGenerateParseRoundTrip(t *testing.T) {
+	clock := newFakeClock(testStart)
+	for _, node := range []struct{ machineID, dataCenterID int64 }{{0, 0}, {5, 9}, {MaxMachineID, MaxDataCenterID}} {
+		s, err := NewSnowflake(node.machineID, node.dataCenterID, WithClock(clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for seq := int64(0); seq < 3; seq++ {
+			id, err := s.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Components{Timestamp: clock.Now(), DataCenterID: node.dataCenterID, MachineID: node.machineID, Sequence: seq}
+			if got := Parse(id); got != want {
+				t.Errorf("Parse(%d) = %+v, want %+v", id, got, want)
+			}
+		}
+		clock.Advance(time.Millisecond)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	s, err := NewSnowflake(7, 3, WithClock(newFakeClock(testStart.Add(678*time.Millisecond))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ts=2024-01-02T03:04:05.678Z unix_ms=1704164645678 epoch_ms=74184245678 dc=3 machine=7 seq=0"
+	if got := Describe(id); got != want {
+		t.Errorf("Describe(%d) = %q, want %q", id, got, want)
+	}
+}
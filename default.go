@@ -0,0 +1,111 @@
+package snowflake
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// 包级默认生成器读取的环境变量
+const (
+	// MachineIDEnv 指定默认生成器的机器 ID，未设置时为 0
+	MachineIDEnv = "SNOWFLAKE_MACHINE_ID"
+	// DataCenterIDEnv 指定默认生成器的数据中心 ID，未设置时为 0
+	DataCenterIDEnv = "SNOWFLAKE_DATACENTER_ID"
+)
+
+// std 是包级函数使用的默认生成器
+var std struct {
+	once sync.Once
+	mu   sync.Mutex // 串行化 SetDefault 与首次生成
+	sf   atomic.Pointer[Snowflake]
+	err  error       // 按环境变量创建默认生成器失败时的错误
+	used atomic.Bool // 默认生成器是否已经发出过 ID
+}
+
+// SetDefault 替换包级函数使用的默认生成器
+//
+// 必须在第一次调用包级的 Generate 或 GenerateBatch 之前调用，否则返回错误：替换一个
+// 已经发出过 ID 的生成器，可能让新旧生成器发出相同的 ID。
+func SetDefault(sf *Snowflake) error {
+	if sf == nil {
+		return errors.New("default generator must not be nil")
+	}
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	if std.used.Load() {
+		return errors.New("default generator has already issued IDs")
+	}
+	std.once.Do(func() {})
+	std.sf.Store(sf)
+	std.err = nil
+	return nil
+}
+
+// Generate 使用默认生成器生成一个唯一 ID
+//
+// 默认生成器在第一次使用时创建，机器 ID 和数据中心 ID 分别从 MachineIDEnv 和
+// DataCenterIDEnv 读取，未设置时为 0；环境变量不合法时 Generate 总是返回错误。部署
+// 多个实例时必须为每个实例设置不同的 ID，或通过 SetDefault 替换默认生成器。
+func Generate() (int64, error) {
+	sf, err := defaultGenerator()
+	if err != nil {
+		return 0, err
+	}
+	return sf.Generate()
+}
+
+// GenerateBatch 使用默认生成器一次性生成 n 个唯一 ID，参见 Snowflake.GenerateBatch
+func GenerateBatch(n int) ([]int64, error) {
+	sf, err := defaultGenerator()
+	if err != nil {
+		return nil, err
+	}
+	return sf.GenerateBatch(n)
+}
+
+// defaultGenerator 返回默认生成器，并把它标记为已经发出过 ID
+//
+// 按环境变量创建失败时不做标记，之后仍然可以通过 SetDefault 设置默认生成器。
+func defaultGenerator() (*Snowflake, error) {
+	std.once.Do(func() {
+		sf, err := newDefault()
+		std.mu.Lock()
+		defer std.mu.Unlock()
+		if sf != nil {
+			std.sf.Store(sf)
+		}
+		std.err = err
+	})
+	if std.used.Load() {
+		// 标记之后默认生成器不会再被替换
+		return std.sf.Load(), nil
+	}
+
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	sf := std.sf.Load()
+	if sf == nil {
+		return nil, std.err
+	}
+	std.used.Store(true)
+	return sf, nil
+}
+
+// newDefault 按照环境变量创建默认生成器
+func newDefault() (*Snowflake, error) {
+	var machineID, dataCenterID int64
+	var err error
+	if _, ok := os.LookupEnv(MachineIDEnv); ok {
+		if machineID, err = MachineIDFromEnv(MachineIDEnv); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := os.LookupEnv(DataCenterIDEnv); ok {
+		if dataCenterID, err = DataCenterIDFromEnv(DataCenterIDEnv); err != nil {
+			return nil, err
+		}
+	}
+	return NewSnowflake(machineID, dataCenterID)
+}
@@ -0,0 +1,104 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// resetDefault 恢复默认生成器的初始状态，供修改全局状态的测试使用
+func resetDefault(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		std.mu.Lock()
+		defer std.mu.Unlock()
+		std.once = sync.Once{}
+		std.sf.Store(nil)
+		std.err = nil
+		std.used.Store(false)
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestDefaultFromEnv(t *testing.T) {
+	resetDefault(t)
+	t.Setenv(MachineIDEnv, "7")
+	t.Setenv(DataCenterIDEnv, "3")
+	id, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := Parse(id); c.MachineID != 7 || c.DataCenterID != 3 {
+		t.Errorf("default generator node = (%d, %d), want (7, 3)", c.MachineID, c.DataCenterID)
+	}
+	ids, err := GenerateBatch(3)
+	if err != nil || len(ids) != 3 || ids[0] <= id {
+		t.Errorf("GenerateBatch = %v, %v; want 3 IDs after %d", ids, err, id)
+	}
+	sf, _ := NewSnowflake(1, 1)
+	if err := SetDefault(sf); err == nil {
+		t.Error("SetDefault after issuing IDs succeeded, want error")
+	}
+}
+
+func TestDefaultBadEnvThenSetDefault(t *testing.T) {
+	resetDefault(t)
+	t.Setenv(MachineIDEnv, "99")
+	if _, err := Generate(); !errors.Is(err, ErrMachineIDRange) {
+		t.Fatalf("Generate with bad env: err = %v, want ErrMachineIDRange", err)
+	}
+	if _, err := Generate(); !errors.Is(err, ErrMachineIDRange) {
+		t.Fatalf("second Generate with bad env: err = %v, want ErrMachineIDRange", err)
+	}
+	sf, err := NewSnowflake(5, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetDefault(sf); err != nil {
+		t.Fatalf("SetDefault after failed env initialization: %v", err)
+	}
+	id, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := Parse(id); c.MachineID != 5 || c.DataCenterID != 6 {
+		t.Errorf("node after SetDefault = (%d, %d), want (5, 6)", c.MachineID, c.DataCenterID)
+	}
+}
+
+func TestDefaultConcurrent(t *testing.T) {
+	resetDefault(t)
+	sf, err := NewSnowflake(2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := map[int64]bool{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		SetDefault(sf)
+	}()
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				id, err := Generate()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate ID %d", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"fmt"
+	"math"
+)
+
+// ToDescendingKey 将 ID 编码为按字典序降序排列的固定宽度字符串
+//
+// 编码结果为 ToBase62(math.MaxInt64 - id)：先用 int64 的最大值减去 ID，再做 11 位
+// 固定宽度的 base62 编码。减法把数值顺序反转，而固定宽度的 base62 保持数值顺序，因此
+// 较大（较新）的 ID 得到字典序较小的键，适合需要按时间倒序扫描的键值存储。按时间
+// 范围扫描时，t1 到 t2 之间的 ID 对应的键区间为
+// [ToDescendingKey(LastIDForTime(t2)), ToDescendingKey(FirstIDForTime(t1))]。
+// id 为负数时 panic，WithUnsigned 实例生成的 ID 需要使用 ToDescendingKeyUint64 编码。
+func ToDescendingKey(id int64) string {
+	if id < 0 {
+		panic(fmt.Sprintf("snowflake: cannot encode negative ID %d as a descending key", id))
+	}
+	return ToBase62(math.MaxInt64 - id)
+}
+
+// FromDescendingKey 解码 ToDescendingKey 生成的键
+//
+// 键必须是 11 位的完整宽度，省略前导字符会改变排序，因此不被接受。
+func FromDescendingKey(s string) (int64, error) {
+	if len(s) != Base62Length {
+		return 0, fmt.Errorf("invalid descending key %q: must be %d characters", s, Base62Length)
+	}
+	v, err := FromBase62(s)
+	if err != nil {
+		return 0, err
+	}
+	return math.MaxInt64 - v, nil
+}
+
+// ToDescendingKeyUint64 与 ToDescendingKey 相同，但用 uint64 的最大值减去 ID 的 uint64 视图
+//
+// 用于 WithUnsigned 实例：最高位为 1 的 ID 同样按时间倒序排列。结果与 ToDescendingKey
+// 的键不在同一个序列中，同一个存储中的键应当只使用其中一种。
+func ToDescendingKeyUint64(id uint64) string {
+	return ToBase62Uint64(math.MaxUint64 - id)
+}
+
+// FromDescendingKeyUint64 解码 ToDescendingKeyUint64 生成的键，宽度规则与 FromDescendingKey 相同
+func FromDescendingKeyUint64(s string) (uint64, error) {
+	if len(s) != Base62Length {
+		return 0, fmt.Errorf("invalid descending key %q: must be %d characters", s, Base62Length)
+	}
+	v, err := FromBase62Uint64(s)
+	if err != nil {
+		return 0, err
+	}
+	return math.MaxUint64 - v, nil
+}
+
+// GenerateDescendingKey 生成唯一的 ID，并以 ToDescendingKey 的格式返回
+//
+// WithUnsigned 实例使用 ToDescendingKeyUint64 的格式，键需要通过 FromDescendingKeyUint64
+// 解码。
+func (s *Snowflake) GenerateDescendingKey() (string, error) {
+	id, err := s.Generate()
+	if err != nil {
+		return "", err
+	}
+	if s.unsigned {
+		return ToDescendingKeyUint64(uint64(id)), nil
+	}
+	return ToDescendingKey(id), nil
+}
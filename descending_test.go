@@ -0,0 +1,71 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+// descendingVectors 是 ToDescendingKey 的黄金向量
+var descendingVectors = []struct {
+	id  int64
+	key string
+}{
+	{0, "AzL8n0Y58m7"},
+	{1, "AzL8n0Y58m6"},
+	{62, "AzL8n0Y58l7"},
+	{1234567890123456789, "9W8o4ykpEOQ"},
+	{math.MaxInt64, "00000000000"},
+}
+
+func TestDescendingKey(t *testing.T) {
+	for _, v := range descendingVectors {
+		if got := ToDescendingKey(v.id); got != v.key {
+			t.Errorf("ToDescendingKey(%d) = %q, want %q", v.id, got, v.key)
+		}
+		got, err := FromDescendingKey(v.key)
+		if err != nil {
+			t.Errorf("FromDescendingKey(%q): %v", v.key, err)
+		} else if got != v.id {
+			t.Errorf("FromDescendingKey(%q) = %d, want %d", v.key, got, v.id)
+		}
+	}
+}
+
+func TestDescendingKeySortOrder(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for i := 0; i < 100; i++ {
+		key, err := s.GenerateDescendingKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		if i%10 == 0 {
+			clock.Advance(time.Millisecond)
+		}
+	}
+	if !sort.IsSorted(sort.Reverse(sort.StringSlice(keys))) {
+		t.Error("keys of increasing IDs are not in descending lexicographic order")
+	}
+}
+
+func TestFromDescendingKeyErrors(t *testing.T) {
+	for _, key := range []string{"", "Y58m7", "AzL8n0Y58m70"} {
+		if _, err := FromDescendingKey(key); err == nil {
+			t.Errorf("FromDescendingKey(%q): want an error for the wrong width", key)
+		}
+	}
+	if _, err := FromDescendingKey("AzL8n0Y58m-"); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf("FromDescendingKey with an invalid character: err = %v, want ErrInvalidCharacter", err)
+	}
+	if v := panicValue(func() { ToDescendingKey(-1) }); v == nil {
+		t.Error("ToDescendingKey(-1) did not panic")
+	}
+}
@@ -0,0 +1,40 @@
+package snowflake
+
+import "testing"
+
+func TestNewDeterministic(t *testing.T) {
+	a, b := NewDeterministic(42), NewDeterministic(42)
+	prev := int64(-1)
+	for i := 0; i < 100; i++ {
+		x, err := a.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, err := b.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != y {
+			t.Fatalf("ID %d: %d != %d with the same seed", i, x, y)
+		}
+		if x <= prev {
+			t.Fatalf("ID %d: %d is not greater than %d", i, x, prev)
+		}
+		if c := a.Parse(x); c.Sequence != 0 {
+			t.Errorf("ID %d: sequence = %d, want 0 since every call enters a new millisecond", i, c.Sequence)
+		}
+		prev = x
+	}
+
+	x, err := NewDeterministic(1).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	y, err := NewDeterministic(2).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x == y {
+		t.Errorf("seeds 1 and 2 produced the same first ID %d", x)
+	}
+}
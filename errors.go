@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrMachineIDRange 表示机器 ID 超出了位布局允许的范围
+var ErrMachineIDRange = errors.New("machine ID out of range")
+
+// ErrDataCenterIDRange 表示数据中心 ID 超出了位布局允许的范围
+var ErrDataCenterIDRange = errors.New("data center ID out of range")
+
+// ErrWorkerIDRange 表示 10 位 worker ID 超出了 [0, MaxWorkerID] 的范围
+var ErrWorkerIDRange = errors.New("worker ID out of range")
+
+// ErrTagRange 表示 GenerateTagged 的标签超出了 WithTagBits 设置的位宽
+var ErrTagRange = errors.New("tag out of range")
+
+// ErrShardRange 表示 GenerateForShard 的分片 ID 超出了位布局提供的范围
+var ErrShardRange = errors.New("shard ID out of range")
+
+// ErrInvalidMachineID 与 ErrMachineIDRange 相同
+var ErrInvalidMachineID = ErrMachineIDRange
+
+// ErrInvalidDataCenterID 与 ErrDataCenterIDRange 相同
+var ErrInvalidDataCenterID = ErrDataCenterIDRange
+
+// ErrClockMovedBackwards 表示系统时钟发生了回拨
+//
+// Generate 返回的 *ClockBackwardsError 会包装该值并附带回拨的时长，可以使用
+// errors.Is 判断。
+var ErrClockMovedBackwards = errors.New("clock moved backwards")
+
+// ErrInvalidCharacter 表示解码的字符串中包含编码字母表以外的字符
+var ErrInvalidCharacter = errors.New("invalid character")
+
+// ErrOverflow 表示解码结果超出了 int64 的范围
+var ErrOverflow = errors.New("value overflows int64")
+
+// ErrEpochOverflow 表示当前时间相对基准时间的偏移已经超出时间戳字段的范围
+//
+// 继续生成会让时间戳溢出到符号位，产生负数或重复的 ID。
+var ErrEpochOverflow = errors.New("timestamp overflows the ID layout")
+
+// ErrClosed 表示生成器已经关闭
+var ErrClosed = errors.New("snowflake generator is closed")
+
+// ErrSequenceExhausted 表示当前毫秒的序列号已经用尽，需要等待下一毫秒
+var ErrSequenceExhausted = errors.New("sequence exhausted for the current millisecond")
+
+// ErrBackfillOverlap 表示 GenerateAt 的时间落在实例实时生成 ID 的时间范围内
+var ErrBackfillOverlap = errors.New("backfill time overlaps live generation")
+
+// ErrNotSnowflakeUUID 表示 UUID 不是由 ID.UUIDv7 生成的
+var ErrNotSnowflakeUUID = errors.New("UUID was not produced from a snowflake ID")
+
+// RangeError 表示机器 ID、数据中心 ID、worker ID、标签或分片 ID 超出了允许的范围
+//
+// Err 为 ErrMachineIDRange、ErrDataCenterIDRange、ErrWorkerIDRange、ErrTagRange 或
+// ErrShardRange，可以使用 errors.Is 判断是哪个参数超出范围，使用 errors.As 取得具体的值和范围。
+type RangeError struct {
+	Err   error // 超出范围的参数对应的哨兵错误
+	Value int64 // 传入的值
+	Max   int64 // 允许的最大值，最小值总是 0
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%v: must be between 0 and %d, got %d", e.Err, e.Max, e.Value)
+}
+
+func (e *RangeError) Unwrap() error { return e.Err }
+
+// ClockBackwardsError 表示生成 ID 时检测到时钟回拨，包装 ErrClockMovedBackwards
+type ClockBackwardsError struct {
+	Backwards time.Duration // 时钟相对上次生成 ID 时回拨的时长
+}
+
+func (e *ClockBackwardsError) Error() string {
+	return fmt.Sprintf("%v, refusing to generate id for %s", ErrClockMovedBackwards, e.Backwards)
+}
+
+func (e *ClockBackwardsError) Unwrap() error { return ErrClockMovedBackwards }
@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIDRangeSentinels(t *testing.T) {
+	if _, err := NewSnowflake(MaxMachineID+1, 0); !errors.Is(err, ErrInvalidMachineID) {
+		t.Errorf("machine ID out of range: err = %v, want ErrInvalidMachineID", err)
+	}
+	if _, err := NewSnowflake(0, MaxDataCenterID+1); !errors.Is(err, ErrInvalidDataCenterID) {
+		t.Errorf("data center ID out of range: err = %v, want ErrInvalidDataCenterID", err)
+	}
+	t.Setenv("SNOWFLAKE_TEST_MACHINE_ID", "32")
+	if _, err := MachineIDFromEnv("SNOWFLAKE_TEST_MACHINE_ID"); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("MachineIDFromEnv out of range: err = %v, want ErrMachineIDRange", err)
+	}
+	if _, err := machineIDFromHostname("api-40", HostnameOrdinal, MaxMachineID); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("hostname ordinal out of range: err = %v, want ErrMachineIDRange", err)
+	}
+}
+
+func TestRangeError(t *testing.T) {
+	s, err := NewSnowflake(1, 1, WithClock(newFakeClock(testStart)), WithTagBits(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name  string
+		err   error
+		want  error
+		value int64
+		max   int64
+	}{
+		{"machine ID", second(NewSnowflake(MaxMachineID+1, 0)), ErrMachineIDRange, MaxMachineID + 1, MaxMachineID},
+		{"data center ID", second(NewSnowflake(0, -1)), ErrDataCenterIDRange, -1, MaxDataCenterID},
+		{"worker ID", second(NewSnowflakeWorker(MaxWorkerID + 1)), ErrWorkerIDRange, MaxWorkerID + 1, MaxWorkerID},
+		{"tag", second(s.GenerateTagged(4)), ErrTagRange, 4, 3},
+		{"shard", second(s.GenerateForShard(1024)), ErrShardRange, 1024, 1023},
+	} {
+		if !errors.Is(tc.err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, tc.err, tc.want)
+		}
+		var re *RangeError
+		if !errors.As(tc.err, &re) {
+			t.Errorf("%s: err = %v, want a *RangeError", tc.name, tc.err)
+			continue
+		}
+		if re.Value != tc.value || re.Max != tc.max {
+			t.Errorf("%s: RangeError{Value: %d, Max: %d}, want {Value: %d, Max: %d}", tc.name, re.Value, re.Max, tc.value, tc.max)
+		}
+		for _, other := range []error{ErrMachineIDRange, ErrDataCenterIDRange, ErrWorkerIDRange, ErrTagRange, ErrShardRange} {
+			if other != tc.want && errors.Is(tc.err, other) {
+				t.Errorf("%s: err = %v also matches %v", tc.name, tc.err, other)
+			}
+		}
+	}
+}
+
+func TestClockBackwardsError(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		clock := newFakeClock(testStart)
+		opts := []Option{WithClock(clock)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(-3 * time.Millisecond)
+		_, err = s.Generate()
+		var cbe *ClockBackwardsError
+		if !errors.As(err, &cbe) {
+			t.Fatalf("lock-free %t: err = %v, want a *ClockBackwardsError", lockFree, err)
+		}
+		if cbe.Backwards != 3*time.Millisecond {
+			t.Errorf("lock-free %t: Backwards = %s, want 3ms", lockFree, cbe.Backwards)
+		}
+		if !errors.Is(err, ErrClockMovedBackwards) {
+			t.Errorf("lock-free %t: err = %v, want ErrClockMovedBackwards", lockFree, err)
+		}
+	}
+}
+
+func TestFailureSentinels(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithSequenceBits(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.NextWithin(0); !errors.Is(err, ErrSequenceExhausted) {
+		t.Errorf("NextWithin(0) with an exhausted sequence: err = %v, want ErrSequenceExhausted", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Generate(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Generate after Close: err = %v, want ErrClosed", err)
+	}
+}
+
+// second 返回两个返回值中的错误，便于在表格中直接写出调用
+func second[T any](_ T, err error) error { return err }
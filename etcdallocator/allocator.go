@@ -0,0 +1,206 @@
+// Package etcdallocator 基于 etcd 租约和事务为 Snowflake 生成器分配数据中心 ID 和机器 ID。
+//
+// 每个 (数据中心 ID, 机器 ID) 组合对应前缀下的一个键，键绑定到实例自己的租约上，
+// 通过 KeepAlive 续期。实例崩溃后租约过期，键随之删除，槽位自动释放。为了不引入
+// etcd 客户端依赖，分配器只依赖 Client 接口，调用方可以用 clientv3 实现它。
+package etcdallocator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bart-k/snowflake"
+)
+
+// Slots 是可分配的槽位总数，即数据中心 ID 与机器 ID 的全部组合
+const Slots = (snowflake.MaxDataCenterID + 1) * (snowflake.MaxMachineID + 1)
+
+// defaultTTL 是租约默认的过期时间
+const defaultTTL = 30 * time.Second
+
+// ErrPoolExhausted 表示所有槽位都已经被占用
+var ErrPoolExhausted = errors.New("etcdallocator: all worker ID slots are taken")
+
+// Client 是分配器需要的最小 etcd 操作集合
+type Client interface {
+	// Grant 创建一个 ttl 的租约并返回租约 ID
+	Grant(ctx context.Context, ttl time.Duration) (int64, error)
+	// KeepAlive 在后台持续为租约续期，每次续期成功向返回的通道发送一次，
+	// 续期失败或租约过期时关闭通道
+	KeepAlive(ctx context.Context, lease int64) (<-chan struct{}, error)
+	// Revoke 撤销租约，绑定到租约上的键随之删除
+	Revoke(ctx context.Context, lease int64) error
+	// List 返回前缀下的全部键值
+	List(ctx context.Context, prefix string) (map[string]string, error)
+	// Claim 在一个事务中判断 key 不存在或其值等于 value，成功时写入 key=value
+	// 并绑定到 lease，返回是否写入成功
+	Claim(ctx context.Context, key, value string, lease int64) (bool, error)
+}
+
+// Option 用于定制 Allocate 的行为
+type Option func(*config)
+
+type config struct {
+	ttl  time.Duration
+	name string
+}
+
+// WithTTL 设置租约的过期时间，默认为 30 秒
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// WithInstanceName 设置稳定的实例名称，写入槽位键的值
+//
+// 实例重启时优先重新占用值等于该名称的槽位，即使上一次的租约尚未过期。不同实例
+// 必须使用不同的名称，否则会占用同一个槽位。默认使用随机值，重启后不会复用槽位。
+func WithInstanceName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// Allocation 表示一次成功的槽位分配
+type Allocation struct {
+	MachineID    int64
+	DataCenterID int64
+
+	// Lost 在租约丢失时关闭，此后其他实例可能占用同一个槽位，应用应当立即停止
+	// 使用对应的生成器。调用 Release 不会关闭该通道。
+	Lost <-chan struct{}
+
+	client   Client
+	lease    int64
+	cancel   context.CancelFunc
+	released chan struct{}
+	once     sync.Once
+}
+
+// Allocate 在 prefix 下占用一个空闲槽位，并在后台为租约续期，直到调用 Release
+//
+// 所有槽位都被占用时返回 ErrPoolExhausted。
+func Allocate(ctx context.Context, client Client, prefix string, opts ...Option) (*Allocation, error) {
+	cfg := config{ttl: defaultTTL}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.ttl <= 0 {
+		return nil, fmt.Errorf("etcdallocator: TTL must be positive, got %s", cfg.ttl)
+	}
+	if cfg.name == "" {
+		token, err := newToken()
+		if err != nil {
+			return nil, err
+		}
+		cfg.name = token
+	}
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+
+	lease, err := client.Grant(ctx, cfg.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("etcdallocator: grant lease: %w", err)
+	}
+	slot, err := claim(ctx, client, prefix, cfg.name, lease)
+	if err != nil {
+		client.Revoke(context.Background(), lease)
+		return nil, err
+	}
+
+	keepCtx, cancel := context.WithCancel(context.Background())
+	alive, err := client.KeepAlive(keepCtx, lease)
+	if err != nil {
+		cancel()
+		client.Revoke(context.Background(), lease)
+		return nil, fmt.Errorf("etcdallocator: keep lease alive: %w", err)
+	}
+	lost := make(chan struct{})
+	a := &Allocation{
+		MachineID:    slot & snowflake.MaxMachineID,
+		DataCenterID: slot >> snowflake.MachineBits,
+		Lost:         lost,
+		client:       client,
+		lease:        lease,
+		cancel:       cancel,
+		released:     make(chan struct{}),
+	}
+	go a.watch(alive, lost)
+	return a, nil
+}
+
+// claim 优先占用值等于 name 的槽位，否则按顺序占用第一个空闲槽位
+func claim(ctx context.Context, client Client, prefix, name string, lease int64) (int64, error) {
+	existing, err := client.List(ctx, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("etcdallocator: list %s: %w", prefix, err)
+	}
+	for key, value := range existing {
+		if value != name {
+			continue
+		}
+		slot, err := strconv.ParseInt(strings.TrimPrefix(key, prefix), 10, 64)
+		if err != nil || slot < 0 || slot >= Slots {
+			continue
+		}
+		if ok, err := client.Claim(ctx, key, name, lease); err != nil {
+			return 0, fmt.Errorf("etcdallocator: claim %s: %w", key, err)
+		} else if ok {
+			return slot, nil
+		}
+	}
+	for slot := int64(0); slot < Slots; slot++ {
+		key := prefix + strconv.FormatInt(slot, 10)
+		if _, taken := existing[key]; taken {
+			continue
+		}
+		ok, err := client.Claim(ctx, key, name, lease)
+		if err != nil {
+			return 0, fmt.Errorf("etcdallocator: claim %s: %w", key, err)
+		}
+		if ok {
+			return slot, nil
+		}
+	}
+	return 0, ErrPoolExhausted
+}
+
+// watch 消费续期通知，通道在 Release 之前关闭时说明租约已经丢失
+func (a *Allocation) watch(alive <-chan struct{}, lost chan<- struct{}) {
+	for range alive {
+	}
+	select {
+	case <-a.released:
+	default:
+		close(lost)
+	}
+}
+
+// Release 停止续期并撤销租约，释放占用的槽位，可以安全地多次调用
+func (a *Allocation) Release(ctx context.Context) error {
+	var err error
+	a.once.Do(func() {
+		close(a.released)
+		a.cancel()
+		if err = a.client.Revoke(ctx, a.lease); err != nil {
+			err = fmt.Errorf("etcdallocator: revoke lease: %w", err)
+		}
+	})
+	return err
+}
+
+// newToken 生成默认的实例名称
+func newToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("etcdallocator: generate instance name: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
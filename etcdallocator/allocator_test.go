@@ -0,0 +1,172 @@
+package etcdallocator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClient 是内存中的 Client，expire 可以模拟租约过期
+type fakeClient struct {
+	mu        sync.Mutex
+	nextLease int64
+	keys      map[string]entry
+	alive     map[int64]chan struct{} // 每个租约的 KeepAlive 通道
+}
+
+type entry struct {
+	value string
+	lease int64
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{keys: make(map[string]entry), alive: make(map[int64]chan struct{})}
+}
+
+func (c *fakeClient) Grant(context.Context, time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextLease++
+	return c.nextLease, nil
+}
+
+func (c *fakeClient) KeepAlive(ctx context.Context, lease int64) (<-chan struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan struct{})
+	c.alive[lease] = ch
+	go func() {
+		<-ctx.Done()
+		c.closeAlive(lease)
+	}()
+	return ch, nil
+}
+
+// closeAlive 关闭租约的 KeepAlive 通道，可以重复调用
+func (c *fakeClient) closeAlive(lease int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ch, ok := c.alive[lease]; ok {
+		close(ch)
+		delete(c.alive, lease)
+	}
+}
+
+func (c *fakeClient) Revoke(_ context.Context, lease int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.keys {
+		if e.lease == lease {
+			delete(c.keys, key)
+		}
+	}
+	return nil
+}
+
+// expire 模拟租约过期：删除绑定的键并关闭 KeepAlive 通道
+func (c *fakeClient) expire(lease int64) {
+	c.Revoke(context.Background(), lease)
+	c.closeAlive(lease)
+}
+
+func (c *fakeClient) List(_ context.Context, prefix string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := make(map[string]string)
+	for key, e := range c.keys {
+		if strings.HasPrefix(key, prefix) {
+			m[key] = e.value
+		}
+	}
+	return m, nil
+}
+
+func (c *fakeClient) Claim(_ context.Context, key, value string, lease int64) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.keys[key]; ok && e.value != value {
+		return false, nil
+	}
+	c.keys[key] = entry{value: value, lease: lease}
+	return true, nil
+}
+
+func TestAllocateDistinctSlots(t *testing.T) {
+	client := newFakeClient()
+	seen := make(map[[2]int64]bool, Slots)
+	for i := 0; i < Slots; i++ {
+		a, err := Allocate(context.Background(), client, "/snowflake")
+		if err != nil {
+			t.Fatalf("Allocate %d: %v", i, err)
+		}
+		defer a.Release(context.Background())
+		node := [2]int64{a.MachineID, a.DataCenterID}
+		if seen[node] {
+			t.Fatalf("slot (%d, %d) allocated twice", a.MachineID, a.DataCenterID)
+		}
+		seen[node] = true
+	}
+	if _, err := Allocate(context.Background(), client, "/snowflake"); !errors.Is(err, ErrPoolExhausted) {
+		t.Errorf("Allocate with every slot taken: err = %v, want ErrPoolExhausted", err)
+	}
+}
+
+func TestAllocateReclaimsInstanceSlot(t *testing.T) {
+	client := newFakeClient()
+	if _, err := Allocate(context.Background(), client, "/snowflake"); err != nil {
+		t.Fatal(err)
+	}
+	first, err := Allocate(context.Background(), client, "/snowflake", WithInstanceName("api-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 重启后上一次的租约尚未过期，同名实例仍然拿回原来的槽位
+	again, err := Allocate(context.Background(), client, "/snowflake", WithInstanceName("api-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.MachineID != first.MachineID || again.DataCenterID != first.DataCenterID {
+		t.Errorf("restarted instance got slot (%d, %d), want (%d, %d)",
+			again.MachineID, again.DataCenterID, first.MachineID, first.DataCenterID)
+	}
+}
+
+func TestLostAndRelease(t *testing.T) {
+	client := newFakeClient()
+	a, err := Allocate(context.Background(), client, "/snowflake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.expire(a.lease)
+	select {
+	case <-a.Lost:
+	case <-time.After(time.Second):
+		t.Fatal("Lost was not closed after the lease expired")
+	}
+
+	b, err := Allocate(context.Background(), client, "/snowflake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Release(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Release(context.Background()); err != nil {
+		t.Errorf("second Release: %v", err)
+	}
+	if keys, _ := client.List(context.Background(), "/snowflake/"); len(keys) != 0 {
+		t.Errorf("keys after Release = %v, want none", keys)
+	}
+	select {
+	case <-b.Lost:
+		t.Error("Lost was closed by Release")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if _, err := Allocate(context.Background(), client, "/snowflake", WithTTL(0)); err == nil {
+		t.Error("zero TTL: err = nil, want an error")
+	}
+}
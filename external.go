@@ -0,0 +1,33 @@
+package snowflake
+
+import "time"
+
+// 外部系统的 Snowflake 基准时间（Unix 毫秒）
+const (
+	// TwitterEpoch 是 Twitter Snowflake 的基准时间 2010-11-04T01:42:54.657Z
+	TwitterEpoch = 1288834974657
+	// DiscordEpoch 是 Discord Snowflake 的基准时间 2015-01-01T00:00:00Z
+	DiscordEpoch = 1420070400000
+)
+
+// ParseWithEpoch 按照默认位布局和给定的基准时间（Unix 毫秒）拆解 ID，不做任何校验
+//
+// 适用于与本包位布局相同、只是基准时间不同的外部系统生成的 ID。
+func ParseWithEpoch(id int64, epoch int64) Components {
+	return defaultLayout.parse(id, epoch, time.Millisecond)
+}
+
+// ParseTwitter 拆解 Twitter 生成的 Snowflake ID
+//
+// Twitter 的 10 位 worker 同样分为 5 位数据中心 ID 和 5 位机器 ID，与本包的位布局一致。
+func ParseTwitter(id int64) Components {
+	return ParseWithEpoch(id, TwitterEpoch)
+}
+
+// ParseDiscord 拆解 Discord 生成的 Snowflake ID
+//
+// Discord 的 10 位 worker 分为高 5 位的 internal worker ID 和低 5 位的 process ID，
+// 分别对应 Components 的 DataCenterID 和 MachineID，Sequence 对应 increment。
+func ParseDiscord(id int64) Components {
+	return ParseWithEpoch(id, DiscordEpoch)
+}
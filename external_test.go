@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDiscord(t *testing.T) {
+	// Discord 开发者文档中拆解 Snowflake 的示例
+	c := ParseDiscord(175928847299117063)
+	want := Components{
+		Timestamp:    time.Date(2016, 4, 30, 11, 18, 25, 796*int(time.Millisecond), time.UTC),
+		DataCenterID: 1,
+		MachineID:    0,
+		Sequence:     7,
+	}
+	if c != want {
+		t.Errorf("ParseDiscord = %+v, want %+v", c, want)
+	}
+}
+
+func TestParseTwitter(t *testing.T) {
+	c := ParseTwitter(1541815603606036480)
+	want := Components{
+		Timestamp:    time.Date(2022, 6, 28, 16, 7, 40, 105*int(time.Millisecond), time.UTC),
+		DataCenterID: 11,
+		MachineID:    26,
+		Sequence:     0,
+	}
+	if c != want {
+		t.Errorf("ParseTwitter = %+v, want %+v", c, want)
+	}
+	if got := c.WorkerID(); got != 11<<5|26 {
+		t.Errorf("WorkerID() = %d, want %d", got, 11<<5|26)
+	}
+}
+
+func TestParseWithEpoch(t *testing.T) {
+	for _, epoch := range []int64{TwitterEpoch, DiscordEpoch, Epoch} {
+		clock := newFakeClock(testStart)
+		s, err := NewSnowflake(7, 3, WithClock(clock), WithEpoch(time.UnixMilli(epoch)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := ParseWithEpoch(id, epoch)
+		if !c.Timestamp.Equal(testStart) || c.MachineID != 7 || c.DataCenterID != 3 {
+			t.Errorf("epoch %d: ParseWithEpoch = %+v, want %s, machine 7, data center 3", epoch, c, testStart)
+		}
+	}
+	if TwitterEpoch != time.Date(2010, 11, 4, 1, 42, 54, 657*int(time.Millisecond), time.UTC).UnixMilli() {
+		t.Error("TwitterEpoch does not match 2010-11-04T01:42:54.657Z")
+	}
+	if DiscordEpoch != time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli() {
+		t.Error("DiscordEpoch does not match 2015-01-01T00:00:00Z")
+	}
+}
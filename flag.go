@@ -0,0 +1,87 @@
+package snowflake
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Set 实现 flag.Value，接受十进制字符串或带 0x 前缀的十六进制字符串
+//
+// 十进制的规则与 UnmarshalText 相同；十六进制不接受符号，超出 int64 范围时返回包装了
+// ErrOverflow 的错误。
+func (id *ID) Set(s string) error {
+	hex, ok := strings.CutPrefix(s, "0x")
+	if !ok {
+		hex, ok = strings.CutPrefix(s, "0X")
+	}
+	if !ok {
+		return id.UnmarshalText([]byte(s))
+	}
+	if hex == "" {
+		return fmt.Errorf("invalid snowflake ID %q: no hex digits", s)
+	}
+	for _, c := range []byte(hex) {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return fmt.Errorf("invalid snowflake ID %q: %w %q", s, ErrInvalidCharacter, c)
+		}
+	}
+	v, err := strconv.ParseInt(hex, 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid snowflake ID %q: %w", s, ErrOverflow)
+	}
+	*id = ID(v)
+	return nil
+}
+
+// MachineIDFlag 在 fs 中定义一个机器 ID 参数，返回保存参数值的指针
+//
+// 参数值按照默认位布局在解析时校验，超出 [0, MaxMachineID] 时由 fs.Parse 报告错误，
+// 例如 invalid value "40" for flag -machine: machine ID out of range: must be between
+// 0 and 31, got 40。
+func MachineIDFlag(fs *flag.FlagSet, name string, value int64, usage string) *int64 {
+	return nodeIDFlag(fs, name, value, usage, MaxMachineID, ErrMachineIDRange)
+}
+
+// DataCenterIDFlag 在 fs 中定义一个数据中心 ID 参数，返回保存参数值的指针
+//
+// 参数值按照默认位布局在解析时校验，超出 [0, MaxDataCenterID] 时由 fs.Parse 报告错误。
+func DataCenterIDFlag(fs *flag.FlagSet, name string, value int64, usage string) *int64 {
+	return nodeIDFlag(fs, name, value, usage, MaxDataCenterID, ErrDataCenterIDRange)
+}
+
+// nodeIDFlag 定义一个在解析时校验范围的节点 ID 参数
+func nodeIDFlag(fs *flag.FlagSet, name string, value int64, usage string, maxID int64, rangeErr error) *int64 {
+	p := new(int64)
+	*p = value
+	fs.Var(&rangeFlag{p: p, max: maxID, err: rangeErr}, name, usage)
+	return p
+}
+
+// rangeFlag 是取值范围为 [0, max] 的 int64 参数
+type rangeFlag struct {
+	p   *int64
+	max int64
+	err error // 超出范围时使用的哨兵错误
+}
+
+func (f *rangeFlag) String() string {
+	if f.p == nil {
+		return "0"
+	}
+	return strconv.FormatInt(*f.p, 10)
+}
+
+func (f *rangeFlag) Set(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return errors.New("must be a decimal integer")
+	}
+	if v < 0 || v > f.max {
+		return &RangeError{Err: f.err, Value: v, Max: f.max}
+	}
+	*f.p = v
+	return nil
+}
@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestIDSet(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want ID
+		err  error // nil 表示成功；errAny 表示任意错误
+	}{
+		{"12345", 12345, nil},
+		{"0x1f", 31, nil},
+		{"0XFF", 255, nil},
+		{"0x7fffffffffffffff", 1<<63 - 1, nil},
+		{"0x8000000000000000", 0, ErrOverflow},
+		{"0x1g", 0, ErrInvalidCharacter},
+		{"0x-1", 0, ErrInvalidCharacter},
+		{"0x", 0, errAny},
+		{"abc", 0, errAny},
+	} {
+		var id ID
+		err := id.Set(tc.in)
+		switch {
+		case tc.err == nil && (err != nil || id != tc.want):
+			t.Errorf("Set(%q) = %d, %v, want %d", tc.in, id, err, tc.want)
+		case tc.err == errAny && err == nil:
+			t.Errorf("Set(%q): want an error", tc.in)
+		case tc.err != nil && tc.err != errAny && !errors.Is(err, tc.err):
+			t.Errorf("Set(%q): err = %v, want %v", tc.in, err, tc.err)
+		}
+	}
+}
+
+// errAny 表示表格中只要求返回错误，不检查具体的哨兵错误
+var errAny = errors.New("any error")
+
+func TestIDFlagSet(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var id ID
+	fs.Var(&id, "id", "snowflake ID")
+	if err := fs.Parse([]string{"-id", "0x2a"}); err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 || fs.Lookup("id").Value.String() != "42" {
+		t.Errorf("-id 0x2a = %d (%s), want 42", id, fs.Lookup("id").Value)
+	}
+}
+
+func TestNodeIDFlags(t *testing.T) {
+	newFlagSet := func() (*flag.FlagSet, *int64, *int64) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		return fs, MachineIDFlag(fs, "machine", 3, "machine ID"), DataCenterIDFlag(fs, "dc", 1, "data center ID")
+	}
+
+	fs, machine, dc := newFlagSet()
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if *machine != 3 || *dc != 1 || fs.Lookup("machine").DefValue != "3" {
+		t.Errorf("defaults = %d, %d, want 3, 1", *machine, *dc)
+	}
+
+	fs, machine, dc = newFlagSet()
+	if err := fs.Parse([]string{"-machine", "31", "-dc", "0"}); err != nil {
+		t.Fatal(err)
+	}
+	if *machine != 31 || *dc != 0 {
+		t.Errorf("-machine 31 -dc 0 = %d, %d", *machine, *dc)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-machine", "40"}, `invalid value "40" for flag -machine: machine ID out of range: must be between 0 and 31, got 40`},
+		{[]string{"-dc", "-1"}, `invalid value "-1" for flag -dc: data center ID out of range: must be between 0 and 31, got -1`},
+		{[]string{"-machine", "0x1"}, `invalid value "0x1" for flag -machine: must be a decimal integer`},
+	} {
+		fs, machine, _ := newFlagSet()
+		err := fs.Parse(tc.args)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Parse(%q): err = %v, want %q", tc.args, err, tc.want)
+		}
+		if *machine != 3 {
+			t.Errorf("Parse(%q) changed the machine ID to %d", tc.args, *machine)
+		}
+	}
+}
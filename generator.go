@@ -0,0 +1,14 @@
+package snowflake
+
+// Generator 是生成唯一 ID 的最小接口，*Snowflake 实现了该接口
+//
+// 依赖 Generator 而不是 *Snowflake 的代码可以在测试中替换为 snowflaketest.FakeGenerator，
+// 断言确定的 ID 而不依赖真实时钟。
+type Generator interface {
+	// Generate 生成一个唯一 ID
+	Generate() (int64, error)
+	// GenerateBatch 一次性生成 n 个严格递增的唯一 ID
+	GenerateBatch(n int) ([]int64, error)
+}
+
+var _ Generator = (*Snowflake)(nil)
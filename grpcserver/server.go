@@ -0,0 +1,89 @@
+// Package grpcserver 将 Snowflake 生成器包装为 gRPC 服务，服务定义见
+// snowflakepb/snowflake.proto。
+//
+// 错误使用标准的 gRPC 状态码，便于拦截器统一处理：请求数量为 0 或 ID 为负数时返回
+// InvalidArgument，请求数量超过上限时返回 ResourceExhausted，生成失败时返回
+// Unavailable。
+//
+// 修改 snowflake.proto 后需要重新生成代码：
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//		--go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//		grpcserver/snowflakepb/snowflake.proto
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/bart-k/snowflake"
+	"github.com/bart-k/snowflake/grpcserver/snowflakepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MaxCount 是 Generate 单次请求允许生成的最大 ID 数量
+const MaxCount = snowflake.MaxBatchSize
+
+// BatchSize 是 Generate 每条流消息中最多包含的 ID 数量
+const BatchSize = 1000
+
+// Server 实现 snowflakepb.IDGeneratorServer
+type Server struct {
+	snowflakepb.UnimplementedIDGeneratorServer
+
+	sf *snowflake.Snowflake
+}
+
+// New 创建一个使用 sf 生成和拆解 ID 的 Server，所有请求共享同一个生成器
+func New(sf *snowflake.Snowflake) *Server {
+	return &Server{sf: sf}
+}
+
+// Register 将使用 sf 的 IDGenerator 服务注册到 s 上
+func Register(s grpc.ServiceRegistrar, sf *snowflake.Snowflake) {
+	snowflakepb.RegisterIDGeneratorServer(s, New(sf))
+}
+
+// Generate 生成 req.Count 个 ID，每 BatchSize 个发送一条消息
+//
+// 同一个请求返回的 ID 严格递增。客户端取消请求时停止生成。
+func (s *Server) Generate(req *snowflakepb.GenerateRequest, stream grpc.ServerStreamingServer[snowflakepb.IDBatch]) error {
+	count := int(req.GetCount())
+	if count == 0 {
+		return status.Error(codes.InvalidArgument, "count must be positive")
+	}
+	if count > MaxCount {
+		return status.Errorf(codes.ResourceExhausted, "count must not exceed %d, got %d", MaxCount, count)
+	}
+	for count > 0 {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		ids, err := s.sf.GenerateBatch(min(count, BatchSize))
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		if err := stream.Send(&snowflakepb.IDBatch{Ids: ids}); err != nil {
+			return err
+		}
+		count -= len(ids)
+	}
+	return nil
+}
+
+// Decode 按照生成器的基准时间和位布局拆解 ID
+func (s *Server) Decode(ctx context.Context, req *snowflakepb.DecodeRequest) (*snowflakepb.Components, error) {
+	c, err := s.sf.Decompose(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &snowflakepb.Components{
+		Id:           req.GetId(),
+		Timestamp:    timestamppb.New(c.Timestamp),
+		DataCenterId: c.DataCenterID,
+		MachineId:    c.MachineID,
+		Sequence:     c.Sequence,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/bart-k/snowflake"
+	"github.com/bart-k/snowflake/grpcserver/snowflakepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient 启动一个基于 bufconn 的服务端，返回连接到它的客户端
+func newClient(t *testing.T) snowflakepb.IDGeneratorClient {
+	t.Helper()
+	sf, err := snowflake.NewSnowflake(3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	Register(srv, sf)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return snowflakepb.NewIDGeneratorClient(conn)
+}
+
+func TestGenerateStream(t *testing.T) {
+	client := newClient(t)
+	const count = 2*BatchSize + 1
+	stream, err := client.Generate(context.Background(), &snowflakepb.GenerateRequest{Count: count})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	var batches int
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(batch.GetIds()) > BatchSize {
+			t.Errorf("batch of %d IDs, want at most %d", len(batch.GetIds()), BatchSize)
+		}
+		batches++
+		ids = append(ids, batch.GetIds()...)
+	}
+	if len(ids) != count || batches != 3 {
+		t.Fatalf("received %d IDs in %d batches, want %d in 3", len(ids), batches, count)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not increasing at %d: %d after %d", i, ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestGenerateInvalidCount(t *testing.T) {
+	client := newClient(t)
+	for count, want := range map[uint32]codes.Code{0: codes.InvalidArgument, MaxCount + 1: codes.ResourceExhausted} {
+		stream, err := client.Generate(context.Background(), &snowflakepb.GenerateRequest{Count: count})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if got := status.Code(err); got != want {
+			t.Errorf("count %d: code = %s, want %s", count, got, want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	client := newClient(t)
+	stream, err := client.Generate(context.Background(), &snowflakepb.GenerateRequest{Count: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := batch.GetIds()[0]
+	c, err := client.Decode(context.Background(), &snowflakepb.DecodeRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := snowflake.Parse(id)
+	if c.GetId() != id || !c.GetTimestamp().AsTime().Equal(want.Timestamp) ||
+		c.GetDataCenterId() != 4 || c.GetMachineId() != 3 || c.GetSequence() != want.Sequence {
+		t.Errorf("Decode(%d) = %v, want %+v", id, c, want)
+	}
+
+	if _, err := client.Decode(context.Background(), &snowflakepb.DecodeRequest{Id: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Decode(-1): code = %s, want InvalidArgument", status.Code(err))
+	}
+}
@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: grpcserver/snowflakepb/snowflake.proto
+
+package snowflakepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IDGenerator_Generate_FullMethodName = "/snowflake.v1.IDGenerator/Generate"
+	IDGenerator_Decode_FullMethodName   = "/snowflake.v1.IDGenerator/Decode"
+)
+
+// IDGeneratorClient is the client API for IDGenerator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IDGenerator 提供 Snowflake ID 的生成和拆解
+type IDGeneratorClient interface {
+	// Generate 生成 count 个 ID，分成多个批次以流的形式返回
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IDBatch], error)
+	// Decode 拆解 ID 的各个组成部分
+	Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*Components, error)
+}
+
+type iDGeneratorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIDGeneratorClient(cc grpc.ClientConnInterface) IDGeneratorClient {
+	return &iDGeneratorClient{cc}
+}
+
+func (c *iDGeneratorClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IDBatch], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IDGenerator_ServiceDesc.Streams[0], IDGenerator_Generate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenerateRequest, IDBatch]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDGenerator_GenerateClient = grpc.ServerStreamingClient[IDBatch]
+
+func (c *iDGeneratorClient) Decode(ctx context.Context, in *DecodeRequest, opts ...grpc.CallOption) (*Components, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Components)
+	err := c.cc.Invoke(ctx, IDGenerator_Decode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IDGeneratorServer is the server API for IDGenerator service.
+// All implementations must embed UnimplementedIDGeneratorServer
+// for forward compatibility.
+//
+// IDGenerator 提供 Snowflake ID 的生成和拆解
+type IDGeneratorServer interface {
+	// Generate 生成 count 个 ID，分成多个批次以流的形式返回
+	Generate(*GenerateRequest, grpc.ServerStreamingServer[IDBatch]) error
+	// Decode 拆解 ID 的各个组成部分
+	Decode(context.Context, *DecodeRequest) (*Components, error)
+	mustEmbedUnimplementedIDGeneratorServer()
+}
+
+// UnimplementedIDGeneratorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIDGeneratorServer struct{}
+
+func (UnimplementedIDGeneratorServer) Generate(*GenerateRequest, grpc.ServerStreamingServer[IDBatch]) error {
+	return status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedIDGeneratorServer) Decode(context.Context, *DecodeRequest) (*Components, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decode not implemented")
+}
+func (UnimplementedIDGeneratorServer) mustEmbedUnimplementedIDGeneratorServer() {}
+func (UnimplementedIDGeneratorServer) testEmbeddedByValue()                     {}
+
+// UnsafeIDGeneratorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IDGeneratorServer will
+// result in compilation errors.
+type UnsafeIDGeneratorServer interface {
+	mustEmbedUnimplementedIDGeneratorServer()
+}
+
+func RegisterIDGeneratorServer(s grpc.ServiceRegistrar, srv IDGeneratorServer) {
+	// If the following call pancis, it indicates UnimplementedIDGeneratorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IDGenerator_ServiceDesc, srv)
+}
+
+func _IDGenerator_Generate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GenerateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IDGeneratorServer).Generate(m, &grpc.GenericServerStream[GenerateRequest, IDBatch]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDGenerator_GenerateServer = grpc.ServerStreamingServer[IDBatch]
+
+func _IDGenerator_Decode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDGeneratorServer).Decode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDGenerator_Decode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDGeneratorServer).Decode(ctx, req.(*DecodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IDGenerator_ServiceDesc is the grpc.ServiceDesc for IDGenerator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IDGenerator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "snowflake.v1.IDGenerator",
+	HandlerType: (*IDGeneratorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Decode",
+			Handler:    _IDGenerator_Decode_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Generate",
+			Handler:       _IDGenerator_Generate_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "grpcserver/snowflakepb/snowflake.proto",
+}
@@ -0,0 +1,124 @@
+// Package httpserver 将 Snowflake 生成器包装为 HTTP 服务，便于其他语言以 sidecar 的
+// 方式调用。
+//
+// 所有响应均为 JSON，ID 以十进制字符串编码，避免 JavaScript 等语言丢失精度：
+//
+//	GET /id              {"id":"..."}
+//	GET /ids?count=N     {"ids":["...","..."]}
+//	GET /decode/{id}     {"id":"...","timestamp":"...","data_center_id":1,"machine_id":1,"sequence":0}
+//
+// 参数不合法时返回 400，生成失败时返回 500，错误响应的格式为 {"error":"..."}。
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bart-k/snowflake"
+)
+
+// MaxCount 是 /ids 单次请求允许生成的最大 ID 数量
+const MaxCount = 1000
+
+// New 返回提供 ID 生成和拆解接口的 http.Handler
+//
+// 所有请求共享同一个生成器，Snowflake 本身是并发安全的。拆解按照 sf 的基准时间和
+// 位布局进行。
+func New(sf *snowflake.Snowflake) http.Handler {
+	h := &handler{sf: sf}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /id", h.id)
+	mux.HandleFunc("GET /ids", h.ids)
+	mux.HandleFunc("GET /decode/{id}", h.decode)
+	return mux
+}
+
+type handler struct {
+	sf *snowflake.Snowflake
+}
+
+type idResponse struct {
+	ID string `json:"id"`
+}
+
+type idsResponse struct {
+	IDs []string `json:"ids"`
+}
+
+type decodeResponse struct {
+	ID           string    `json:"id"`
+	Timestamp    time.Time `json:"timestamp"`
+	DataCenterID int64     `json:"data_center_id"`
+	MachineID    int64     `json:"machine_id"`
+	Sequence     int64     `json:"sequence"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *handler) id(w http.ResponseWriter, r *http.Request) {
+	id, err := h.sf.Generate()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, idResponse{ID: strconv.FormatInt(id, 10)})
+}
+
+// ids 生成 count 个 ID，count 缺省为 1，必须在 [1, MaxCount] 范围内
+func (h *handler) ids(w http.ResponseWriter, r *http.Request) {
+	count := 1
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxCount {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("count must be an integer between 1 and %d, got %q", MaxCount, v))
+			return
+		}
+		count = n
+	}
+	ids, err := h.sf.GenerateBatch(count)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := idsResponse{IDs: make([]string, len(ids))}
+	for i, id := range ids {
+		resp.IDs[i] = strconv.FormatInt(id, 10)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *handler) decode(w http.ResponseWriter, r *http.Request) {
+	v := r.PathValue("id")
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid snowflake ID %q", v))
+		return
+	}
+	c, err := h.sf.Decompose(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, decodeResponse{
+		ID:           strconv.FormatInt(id, 10),
+		Timestamp:    c.Timestamp,
+		DataCenterID: c.DataCenterID,
+		MachineID:    c.MachineID,
+		Sequence:     c.Sequence,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
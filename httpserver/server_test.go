@@ -0,0 +1,96 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/bart-k/snowflake"
+)
+
+// get 向 New(sf) 发送 GET 请求，把 JSON 响应解码到 v 并返回状态码
+func get(t *testing.T, sf *snowflake.Snowflake, target string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	New(sf).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s: Content-Type = %q, want application/json", target, ct)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("GET %s: decode %q: %v", target, rec.Body.String(), err)
+	}
+	return rec.Code
+}
+
+func newGenerator(t *testing.T) *snowflake.Snowflake {
+	t.Helper()
+	sf, err := snowflake.NewSnowflake(3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sf
+}
+
+func TestID(t *testing.T) {
+	var resp idResponse
+	if code := get(t, newGenerator(t), "/id", &resp); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if id, err := strconv.ParseInt(resp.ID, 10, 64); err != nil || id <= 0 {
+		t.Errorf("id = %q, want a positive decimal string", resp.ID)
+	}
+}
+
+func TestIDs(t *testing.T) {
+	sf := newGenerator(t)
+	var resp idsResponse
+	if code := get(t, sf, "/ids?count=50", &resp); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if len(resp.IDs) != 50 {
+		t.Fatalf("got %d IDs, want 50", len(resp.IDs))
+	}
+	if code := get(t, sf, "/ids", &resp); code != http.StatusOK || len(resp.IDs) != 1 {
+		t.Errorf("default count: status %d with %d IDs, want 200 with 1", code, len(resp.IDs))
+	}
+	for _, count := range []string{"0", "-1", "1001", "x"} {
+		var e errorResponse
+		if code := get(t, sf, "/ids?count="+count, &e); code != http.StatusBadRequest || e.Error == "" {
+			t.Errorf("count=%s: status %d, error %q, want 400 with a message", count, code, e.Error)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	sf := newGenerator(t)
+	id, err := sf.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp decodeResponse
+	if code := get(t, sf, "/decode/"+strconv.FormatInt(id, 10), &resp); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	want := snowflake.Parse(id)
+	if resp.ID != strconv.FormatInt(id, 10) || !resp.Timestamp.Equal(want.Timestamp) ||
+		resp.DataCenterID != 4 || resp.MachineID != 3 || resp.Sequence != want.Sequence {
+		t.Errorf("decode = %+v, want %+v", resp, want)
+	}
+	for _, v := range []string{"abc", "-1"} {
+		var e errorResponse
+		if code := get(t, sf, "/decode/"+v, &e); code != http.StatusBadRequest {
+			t.Errorf("decode %s: status = %d, want 400", v, code)
+		}
+	}
+}
+
+func TestGenerateFailure(t *testing.T) {
+	sf := newGenerator(t)
+	sf.Close()
+	var e errorResponse
+	if code := get(t, sf, "/id", &e); code != http.StatusInternalServerError || e.Error == "" {
+		t.Errorf("closed generator: status %d, error %q, want 500 with a message", code, e.Error)
+	}
+}
@@ -0,0 +1,64 @@
+package snowflake
+
+import (
+	"strconv"
+	"time"
+)
+
+// ID 是带有语义的 Snowflake ID，可以直接拆解出各个组成部分
+type ID int64
+
+// GenerateID 生成唯一的 Snowflake ID，并以 ID 类型返回
+func (s *Snowflake) GenerateID() (ID, error) {
+	id, err := s.Generate()
+	if err != nil {
+		return 0, err
+	}
+	return ID(id), nil
+}
+
+// Int64 返回 ID 的 int64 形式
+func (id ID) Int64() int64 {
+	return int64(id)
+}
+
+// Time 返回 ID 生成时的毫秒时间（UTC）
+//
+// ID 的方法均按照默认的 Epoch 和位布局解析，自定义配置的实例请使用
+// Snowflake.Decompose。
+func (id ID) Time() time.Time {
+	return time.UnixMilli((int64(id) >> TimestampShift) + Epoch).UTC()
+}
+
+// DataCenterID 返回 ID 中的数据中心 ID
+func (id ID) DataCenterID() int64 {
+	return (int64(id) >> DataCenterShift) & MaxDataCenterID
+}
+
+// MachineID 返回 ID 中的机器 ID
+func (id ID) MachineID() int64 {
+	return (int64(id) >> MachineShift) & MaxMachineID
+}
+
+// Sequence 返回 ID 中的毫秒内序列号
+func (id ID) Sequence() int64 {
+	return int64(id) & MaxSequence
+}
+
+// String 返回 ID 的十进制字符串
+func (id ID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// GenerateDecimalString 生成唯一的 ID，并以十进制字符串返回
+//
+// 编码在栈上的缓冲区中完成，除返回的字符串外不分配内存，适合生成后直接写入日志或
+// HTTP 头的场景。
+func (s *Snowflake) GenerateDecimalString() (string, error) {
+	id, err := s.Generate()
+	if err != nil {
+		return "", err
+	}
+	var buf [19]byte // int64 的最大值有 19 位十进制数字
+	return string(strconv.AppendInt(buf[:0], id, 10)), nil
+}
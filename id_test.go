@@ -0,0 +1,72 @@
+package snowflake
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestIDAccessors(t *testing.T) {
+	s, err := NewSnowflake(7, 3, WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.GenerateID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !id.Time().Equal(testStart) || id.DataCenterID() != 3 || id.MachineID() != 7 || id.Sequence() != 1 {
+		t.Errorf("ID %d: time %s, data center %d, machine %d, sequence %d; want %s, 3, 7, 1",
+			id, id.Time(), id.DataCenterID(), id.MachineID(), id.Sequence(), testStart)
+	}
+	if got, want := id.String(), strconv.FormatInt(id.Int64(), 10); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateDecimalString(t *testing.T) {
+	s, err := NewSnowflake(7, 3, WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	str, err := s.GenerateDecimalString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := s.Parse(id); !c.Timestamp.Equal(testStart) || c.MachineID != 7 || c.DataCenterID != 3 || c.Sequence != 0 {
+		t.Errorf("GenerateDecimalString() = %q, parsed as %+v", str, c)
+	}
+
+	// 除返回的字符串外不分配内存
+	s, err = NewSnowflake(7, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(1000, func() {
+		if _, err := s.GenerateDecimalString(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 1 {
+		t.Errorf("GenerateDecimalString allocates %v times per call, want at most 1", allocs)
+	}
+}
+
+func BenchmarkGenerateDecimalString(b *testing.B) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.GenerateDecimalString(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MarshalJSON 将 ID 编码为带引号的十进制字符串
+//
+// Snowflake ID 超出了 2^53，以 JSON 数字传给 JavaScript 客户端会丢失精度，
+// 因此统一以字符串形式输出。
+func (id ID) MarshalJSON() ([]byte, error) {
+	buf := make([]byte, 0, 22)
+	buf = append(buf, '"')
+	buf = strconv.AppendInt(buf, int64(id), 10)
+	buf = append(buf, '"')
+	return buf, nil
+}
+
+// UnmarshalJSON 同时接受带引号的十进制字符串和 JSON 数字
+//
+// JSON null 保持 ID 不变；空字符串、非十进制整数（包括浮点数）以及负数都返回错误。
+func (id *ID) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+		if s == "" {
+			return fmt.Errorf("invalid snowflake ID: empty string")
+		}
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid snowflake ID %s: %w", data, err)
+	}
+	if v < 0 {
+		return fmt.Errorf("invalid snowflake ID %s: must not be negative", data)
+	}
+	*id = ID(v)
+	return nil
+}
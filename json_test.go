@@ -0,0 +1,50 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIDJSON(t *testing.T) {
+	type payload struct {
+		ID ID `json:"id"`
+	}
+	in := payload{ID: 1234567890123456789}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"id":"1234567890123456789"}`; got != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+	var out payload
+	if err := json.Unmarshal(data, &out); err != nil || out != in {
+		t.Errorf("Unmarshal(%s) = %+v, %v, want %+v", data, out, err, in)
+	}
+
+	// 同时接受 JSON 数字，null 保持原值
+	if err := json.Unmarshal([]byte(`{"id":42}`), &out); err != nil || out.ID != 42 {
+		t.Errorf("Unmarshal number = %d, %v, want 42", out.ID, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":null}`), &out); err != nil || out.ID != 42 {
+		t.Errorf("Unmarshal null = %d, %v, want the ID unchanged", out.ID, err)
+	}
+	for _, in := range []string{`""`, `"abc"`, `1.5`, `"9223372036854775808"`} {
+		var id ID
+		if err := json.Unmarshal([]byte(in), &id); err == nil {
+			t.Errorf("Unmarshal(%s): err = nil, want an error", in)
+		}
+	}
+}
+
+func TestIDUnmarshalJSONNegative(t *testing.T) {
+	for _, in := range []string{`"-1"`, `-1`} {
+		id := ID(7)
+		if err := json.Unmarshal([]byte(in), &id); err == nil {
+			t.Errorf("Unmarshal(%s): err = nil, want an error", in)
+		}
+		if id != 7 {
+			t.Errorf("Unmarshal(%s) changed the ID to %d", in, id)
+		}
+	}
+}
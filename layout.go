@@ -0,0 +1,148 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxLayoutBits 是数据中心 ID、机器 ID 和序列号位宽之和的上限，
+// 保证时间戳至少保留 41 位且 ID 始终为正数
+const maxLayoutBits = 63 - 41
+
+// Layout 描述 ID 中数据中心 ID、机器 ID 和序列号的位宽，时间戳占用 63 位中剩余的高位
+//
+// 三个字段的位宽之和不能超过 22 位，以保证时间戳至少保留 41 位。Epoch 和 TimeUnit
+// 描述时间戳的含义，零值分别表示包级的 Epoch 和 1 毫秒。通过 Snowflake.Layout 取得
+// 实例的完整布局后，可以在其他服务中用 Layout.Parse 拆解它生成的 ID。
+type Layout struct {
+	MachineBits    int           // 机器 ID 位宽
+	DataCenterBits int           // 数据中心 ID 位宽
+	SequenceBits   int           // 序列号位宽
+	Epoch          int64         // 基准时间（Unix 毫秒），0 表示 Epoch
+	TimeUnit       time.Duration // 时间戳的时间单位，0 表示 1 毫秒
+}
+
+// defaultLayout 是与包级常量一致的默认位布局
+var defaultLayout = Layout{
+	MachineBits:    MachineBits,
+	DataCenterBits: DataCenterBits,
+	SequenceBits:   SequenceBits,
+}
+
+// validate 检查位布局是否合法，三个字段的位宽之和不能超过 maxBits
+func (l Layout) validate(maxBits int) error {
+	if l.MachineBits < 0 || l.DataCenterBits < 0 || l.SequenceBits < 0 {
+		return fmt.Errorf("bit widths must not be negative, got machine=%d data center=%d sequence=%d",
+			l.MachineBits, l.DataCenterBits, l.SequenceBits)
+	}
+	if total := l.MachineBits + l.DataCenterBits + l.SequenceBits; total > maxBits {
+		return fmt.Errorf("machine, data center and sequence bits must sum to at most %d, got %d", maxBits, total)
+	}
+	return nil
+}
+
+func (l Layout) maxMachineID() int64    { return -1 ^ (-1 << l.MachineBits) }
+func (l Layout) maxDataCenterID() int64 { return -1 ^ (-1 << l.DataCenterBits) }
+func (l Layout) maxSequence() int64     { return -1 ^ (-1 << l.SequenceBits) }
+
+// maxTimestamp 返回时间戳字段能表示的最大值
+func (l Layout) maxTimestamp() int64 { return -1 ^ (-1 << (63 - l.timestampShift())) }
+
+func (l Layout) machineShift() int    { return l.SequenceBits }
+func (l Layout) dataCenterShift() int { return l.SequenceBits + l.MachineBits }
+func (l Layout) timestampShift() int  { return l.SequenceBits + l.MachineBits + l.DataCenterBits }
+
+// parse 按照位布局、基准时间（Unix 毫秒）和时间单位拆解 ID
+func (l Layout) parse(id int64, epoch int64, unit time.Duration) Components {
+	return Components{
+		Timestamp:    unitTime(epoch, uint64(id)>>l.timestampShift(), unit),
+		DataCenterID: (id >> l.dataCenterShift()) & l.maxDataCenterID(),
+		MachineID:    (id >> l.machineShift()) & l.maxMachineID(),
+		Sequence:     id & l.maxSequence(),
+	}
+}
+
+// WithMachineBits 设置机器 ID 的位宽，默认为 MachineBits
+func WithMachineBits(n int) Option {
+	return func(s *Snowflake) {
+		s.layout.MachineBits = n
+	}
+}
+
+// WithDataCenterBits 设置数据中心 ID 的位宽，默认为 DataCenterBits
+func WithDataCenterBits(n int) Option {
+	return func(s *Snowflake) {
+		s.layout.DataCenterBits = n
+	}
+}
+
+// WithSequenceBits 设置序列号的位宽，默认为 SequenceBits
+func WithSequenceBits(n int) Option {
+	return func(s *Snowflake) {
+		s.layout.SequenceBits = n
+	}
+}
+
+// Decompose 按照位布局、基准时间和时间单位拆解 ID，负数 ID 返回错误
+func (l Layout) Decompose(id int64) (Components, error) {
+	if id < 0 {
+		return Components{}, fmt.Errorf("invalid snowflake ID %d: must not be negative", id)
+	}
+	return l.Parse(id), nil
+}
+
+// Parse 按照位布局、基准时间和时间单位拆解 ID，不做任何校验
+//
+// 时间戳按无符号数读取，因此也适用于 WithUnsigned 实例生成的 ID。
+func (l Layout) Parse(id int64) Components {
+	epoch, unit := l.Epoch, l.TimeUnit
+	if epoch == 0 {
+		epoch = Epoch
+	}
+	if unit == 0 {
+		unit = time.Millisecond
+	}
+	return l.parse(id, epoch, unit)
+}
+
+// Layout 返回实例的完整布局，包括位宽、基准时间和时间单位
+//
+// Sonyflake 兼容模式的字段顺序与 Layout 不同，其 ID 需要使用 ParseSonyflake 拆解；
+// 启用 WithTagBits 时 Layout.Parse 返回的 Sequence 包含标签位。
+func (s *Snowflake) Layout() Layout {
+	l := s.layout
+	l.Epoch, l.TimeUnit = s.epoch, s.unit
+	return l
+}
+
+// WithLayout 设置实例使用的位布局，等价于同时设置三个字段的位宽
+//
+// l.Epoch 和 l.TimeUnit 不为零时同时设置实例的基准时间和时间单位。
+func WithLayout(l Layout) Option {
+	return func(s *Snowflake) {
+		s.layout = Layout{MachineBits: l.MachineBits, DataCenterBits: l.DataCenterBits, SequenceBits: l.SequenceBits}
+		if l.Epoch != 0 {
+			s.epoch = l.Epoch
+		}
+		if l.TimeUnit != 0 {
+			s.unit = l.TimeUnit
+		}
+	}
+}
+
+// NewSnowflakeWithLayout 创建一个使用指定位布局的 Snowflake 实例
+//
+// machineID 和 dataCenterID 按照 layout 中的位宽校验，生成的 ID 需要通过
+// Snowflake.Decompose 或 Layout.Decompose 拆解。
+func NewSnowflakeWithLayout(layout Layout, machineID int64, dataCenterID int64, opts ...Option) (*Snowflake, error) {
+	return NewSnowflake(machineID, dataCenterID, append([]Option{WithLayout(layout)}, opts...)...)
+}
+
+// NewWithConfig 创建一个使用自定义位布局的 Snowflake 实例
+//
+// 通过 WithMachineBits、WithDataCenterBits 和 WithSequenceBits 调整各字段的位宽，
+// 三者之和不能超过 22 位，以便时间戳保留至少 41 位。machineID 和 dataCenterID
+// 按照调整后的位宽校验。
+func NewWithConfig(machineID int64, dataCenterID int64, opts ...Option) (*Snowflake, error) {
+	return NewSnowflake(machineID, dataCenterID, opts...)
+}
@@ -0,0 +1,148 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewWithConfigBits(t *testing.T) {
+	s, err := NewWithConfig(200, 1, WithMachineBits(8), WithDataCenterBits(2), WithSequenceBits(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := s.Parse(id); c.MachineID != 200 || c.DataCenterID != 1 {
+		t.Errorf("Parse(%d) = %+v, want machine 200 and data center 1", id, c)
+	}
+
+	if _, err := NewWithConfig(256, 0, WithMachineBits(8), WithDataCenterBits(2)); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("machine ID 256 with 8 bits: err = %v, want ErrMachineIDRange", err)
+	}
+	if _, err := NewWithConfig(0, 0, WithMachineBits(10), WithDataCenterBits(1)); err == nil {
+		t.Error("23 layout bits: err = nil, want an error")
+	}
+	if _, err := NewWithConfig(0, 0, WithSequenceBits(-1)); err == nil {
+		t.Error("negative sequence bits: err = nil, want an error")
+	}
+}
+
+func TestNewSnowflakeWithLayout(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		layout           Layout
+		machine, dataCtr int64
+		sequencePerTick  int
+	}{
+		{"wide machine", Layout{MachineBits: 10, DataCenterBits: 0, SequenceBits: 12}, 1000, 0, 4096},
+		{"wide sequence", Layout{MachineBits: 3, DataCenterBits: 3, SequenceBits: 16}, 5, 6, 1 << 16},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock(testStart)
+			s, err := NewSnowflakeWithLayout(tc.layout, tc.machine, tc.dataCtr, WithClock(clock))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tc.sequencePerTick; i++ {
+				id, err := s.Generate()
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := Components{Timestamp: testStart, DataCenterID: tc.dataCtr, MachineID: tc.machine, Sequence: int64(i)}
+				if got := tc.layout.Parse(id); got != want {
+					t.Fatalf("Layout.Parse(%d) = %+v, want %+v", id, got, want)
+				}
+			}
+			// 序列号用尽后进入下一毫秒
+			id, err := s.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c := s.Parse(id); !c.Timestamp.Equal(testStart.Add(time.Millisecond)) || c.Sequence != 0 {
+				t.Errorf("ID after %d per tick = %+v, want the next millisecond with sequence 0", tc.sequencePerTick, c)
+			}
+		})
+	}
+}
+
+func TestSnowflakeLayoutParse(t *testing.T) {
+	epoch := testStart.Add(-time.Hour)
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"epoch and unit", []Option{WithEpoch(epoch), WithTimeUnit(10 * time.Millisecond)}},
+		{"custom bits", []Option{WithLayout(Layout{MachineBits: 8, SequenceBits: 14}), WithEpoch(epoch)}},
+		{"unsigned", []Option{WithEpoch(epoch), WithUnsigned()}},
+	} {
+		clock := newFakeClock(testStart)
+		s, err := NewSnowflake(3, 0, append(tc.opts, WithClock(clock))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := s.Layout()
+		if l.Epoch != s.epoch || l.TimeUnit != s.unit {
+			t.Errorf("%s: Layout() = %+v, want epoch %d and unit %s", tc.name, l, s.epoch, s.unit)
+		}
+		ats := []time.Time{testStart}
+		if maxNano := time.Unix(0, math.MaxInt64); s.MaxTime().Before(maxNano) {
+			// 时钟读数以纳秒表示，只有 2262 年之前的最后一个时间单位可以实际生成
+			ats = append(ats, s.MaxTime())
+		}
+		for _, at := range ats {
+			clock.Advance(at.Sub(clock.Now()))
+			id, err := s.Generate()
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if got, want := l.Parse(id), s.Parse(id); got != want || !got.Timestamp.Equal(at) {
+				t.Errorf("%s: Layout().Parse(%d) = %+v, want %+v at %s", tc.name, id, got, want, at)
+			}
+		}
+	}
+}
+
+func TestLayoutParseDefaults(t *testing.T) {
+	id := int64(12345)<<TimestampShift | 2<<DataCenterShift | 5<<MachineShift | 9
+	if got, want := defaultLayout.Parse(id), Parse(id); got != want {
+		t.Errorf("zero Epoch and TimeUnit: Parse = %+v, want %+v", got, want)
+	}
+	if _, err := defaultLayout.Decompose(-1); err == nil {
+		t.Error("Decompose(-1): want an error")
+	}
+	if c, err := defaultLayout.Decompose(id); err != nil || c != Parse(id) {
+		t.Errorf("Decompose(%d) = %+v, %v, want %+v", id, c, err, Parse(id))
+	}
+}
+
+func TestWithLayoutEpochAndUnit(t *testing.T) {
+	l := Layout{
+		MachineBits:    6,
+		DataCenterBits: 4,
+		SequenceBits:   12,
+		Epoch:          testStart.Add(-time.Hour).UnixMilli(),
+		TimeUnit:       time.Second,
+	}
+	s, err := NewSnowflakeWithLayout(l, 63, 15, WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Layout(); got != l {
+		t.Errorf("Layout() = %+v, want %+v", got, l)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id>>l.timestampShift() != 3600 {
+		t.Errorf("timestamp field = %d, want 3600 seconds since the epoch", id>>l.timestampShift())
+	}
+	if c := l.Parse(id); !c.Timestamp.Equal(testStart) || c.MachineID != 63 || c.DataCenterID != 15 {
+		t.Errorf("Parse = %+v", c)
+	}
+}
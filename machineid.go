@@ -0,0 +1,165 @@
+package snowflake
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// MachineIDFromIP 根据本机第一个非回环的私有 IPv4 地址推导机器 ID
+//
+// 网卡按名称排序后依次检查，保证存在多个网卡时结果确定。取地址的低位并按
+// MaxMachineID 掩码，因此低位相同的地址会得到相同的机器 ID。找不到合适的地址时
+// 返回错误。
+func MachineIDFromIP() (int64, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return 0, fmt.Errorf("list network interfaces: %w", err)
+	}
+	sort.Slice(ifaces, func(i, j int) bool { return ifaces[i].Name < ifaces[j].Name })
+
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip := ipNet.IP.To4()
+			if ip == nil || !ip.IsPrivate() {
+				continue
+			}
+			return int64(ip[3]) & MaxMachineID, nil
+		}
+	}
+	return 0, fmt.Errorf("no private IPv4 address found on non-loopback interfaces")
+}
+
+// 主机名推导机器 ID 的常用模式
+const (
+	// HostnameHash 表示对整个主机名做哈希
+	HostnameHash = ""
+	// HostnameOrdinal 匹配主机名末尾的整数，例如 Kubernetes StatefulSet 中 api-7 的 7
+	HostnameOrdinal = `(\d+)$`
+)
+
+// MachineIDFromHostname 根据 os.Hostname 推导机器 ID
+//
+// pattern 为 HostnameHash 时，使用 32 位 FNV-1a 对主机名做哈希，再按 MaxMachineID
+// 掩码，不同主机名有可能得到相同的机器 ID，调用方需要自行评估冲突风险。否则 pattern
+// 是一个正则表达式，其第一个捕获组必须匹配十进制整数（例如 HostnameOrdinal），该整数
+// 即为机器 ID；主机名不匹配或整数超出 [0, MaxMachineID] 时返回错误，不会静默截断。
+func MachineIDFromHostname(pattern string) (int64, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return 0, fmt.Errorf("get hostname: %w", err)
+	}
+	return machineIDFromHostname(hostname, pattern, MaxMachineID)
+}
+
+// machineIDFromHostname 按照 pattern 从 hostname 推导不超过 maxID 的机器 ID
+func machineIDFromHostname(hostname string, pattern string, maxID int64) (int64, error) {
+	if pattern == HostnameHash {
+		h := fnv.New32a()
+		h.Write([]byte(hostname))
+		return int64(h.Sum32()) & maxID, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hostname pattern %q: %w", pattern, err)
+	}
+	if re.NumSubexp() < 1 {
+		return 0, fmt.Errorf("invalid hostname pattern %q: must contain a capture group", pattern)
+	}
+	m := re.FindStringSubmatch(hostname)
+	if m == nil {
+		return 0, fmt.Errorf("hostname %q does not match pattern %q", hostname, pattern)
+	}
+	id, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse machine ID %q from hostname %q: %w", m[1], hostname, err)
+	}
+	if id < 0 || id > maxID {
+		return 0, fmt.Errorf("%w from hostname %q", &RangeError{Err: ErrMachineIDRange, Value: id, Max: maxID}, hostname)
+	}
+	return id, nil
+}
+
+// MachineIDFromEnv 从环境变量 key 中读取机器 ID
+//
+// 环境变量必须是 [0, MaxMachineID] 范围内的十进制整数，未设置、无法解析或超出范围时
+// 返回错误。
+func MachineIDFromEnv(key string) (int64, error) {
+	return idFromEnv(key, "machine ID", MaxMachineID, ErrMachineIDRange)
+}
+
+// DataCenterIDFromEnv 从环境变量 key 中读取数据中心 ID
+//
+// 环境变量必须是 [0, MaxDataCenterID] 范围内的十进制整数，未设置、无法解析或超出范围时
+// 返回错误。
+func DataCenterIDFromEnv(key string) (int64, error) {
+	return idFromEnv(key, "data center ID", MaxDataCenterID, ErrDataCenterIDRange)
+}
+
+// idFromEnv 从环境变量 key 中读取 [0, maxID] 范围内的 ID，超出范围时包装 rangeErr
+func idFromEnv(key string, name string, maxID int64, rangeErr error) (int64, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return 0, fmt.Errorf("environment variable %s is not set", key)
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s from %s: %w", name, key, err)
+	}
+	if id < 0 || id > maxID {
+		return 0, fmt.Errorf("%w from %s", &RangeError{Err: rangeErr, Value: id, Max: maxID}, key)
+	}
+	return id, nil
+}
+
+// WithMachineIDFromHostname 在创建实例时按照 MachineIDFromHostname 的规则推导机器 ID，
+// 覆盖 NewSnowflake 的 machineID 参数
+//
+// 取值范围按照实例的位布局校验。
+func WithMachineIDFromHostname(pattern string) Option {
+	return func(s *Snowflake) {
+		s.machineIDSource = func(maxID int64) (int64, error) {
+			hostname, err := os.Hostname()
+			if err != nil {
+				return 0, fmt.Errorf("get hostname: %w", err)
+			}
+			return machineIDFromHostname(hostname, pattern, maxID)
+		}
+	}
+}
+
+// WithMachineIDFromEnv 在创建实例时从环境变量 key 读取机器 ID，覆盖 NewSnowflake 的
+// machineID 参数
+//
+// 取值范围按照实例的位布局校验。
+func WithMachineIDFromEnv(key string) Option {
+	return func(s *Snowflake) {
+		s.machineIDSource = func(maxID int64) (int64, error) {
+			return idFromEnv(key, "machine ID", maxID, ErrMachineIDRange)
+		}
+	}
+}
+
+// NewSnowflakeAutoMachine 创建一个机器 ID 由 MachineIDFromIP 推导的 Snowflake 实例
+func NewSnowflakeAutoMachine(dataCenterID int64, opts ...Option) (*Snowflake, error) {
+	machineID, err := MachineIDFromIP()
+	if err != nil {
+		return nil, err
+	}
+	return NewSnowflake(machineID, dataCenterID, opts...)
+}
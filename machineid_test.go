@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMachineIDFromIP(t *testing.T) {
+	id, err := MachineIDFromIP()
+	if err != nil {
+		t.Skipf("no private IPv4 address in this environment: %v", err)
+	}
+	if id < 0 || id > MaxMachineID {
+		t.Errorf("MachineIDFromIP() = %d, want within [0, %d]", id, MaxMachineID)
+	}
+	// 网卡按名称排序，结果是确定的
+	if again, err := MachineIDFromIP(); err != nil || again != id {
+		t.Errorf("second MachineIDFromIP() = %d, %v, want %d", again, err, id)
+	}
+}
+
+func TestMachineIDFromHostnameHash(t *testing.T) {
+	a, err := machineIDFromHostname("api-server-a", HostnameHash, MaxMachineID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := machineIDFromHostname("api-server-a", HostnameHash, MaxMachineID); a != b {
+		t.Errorf("hash of the same hostname differs: %d and %d", a, b)
+	}
+	if a < 0 || a > MaxMachineID {
+		t.Errorf("hash machine ID = %d, want within [0, %d]", a, MaxMachineID)
+	}
+}
+
+func TestDataCenterIDFromEnv(t *testing.T) {
+	t.Setenv("SNOWFLAKE_TEST_DC", "17")
+	if id, err := DataCenterIDFromEnv("SNOWFLAKE_TEST_DC"); err != nil || id != 17 {
+		t.Errorf("DataCenterIDFromEnv = %d, %v, want 17", id, err)
+	}
+	for _, v := range []string{"32", "-1", "x"} {
+		t.Setenv("SNOWFLAKE_TEST_DC", v)
+		if _, err := DataCenterIDFromEnv("SNOWFLAKE_TEST_DC"); err == nil {
+			t.Errorf("DataCenterIDFromEnv with %q: err = nil, want an error", v)
+		}
+	}
+	if _, err := DataCenterIDFromEnv("SNOWFLAKE_TEST_UNSET"); err == nil {
+		t.Error("DataCenterIDFromEnv with an unset variable: err = nil, want an error")
+	}
+}
+
+func TestMachineIDFromHostnameOrdinal(t *testing.T) {
+	for _, tc := range []struct {
+		hostname, pattern string
+		maxID, want       int64
+		wantErr           bool
+	}{
+		{"api-7", HostnameOrdinal, MaxMachineID, 7, false},
+		{"api-31", HostnameOrdinal, MaxMachineID, 31, false},
+		{"api-32", HostnameOrdinal, MaxMachineID, 0, true},
+		{"api-200", HostnameOrdinal, 255, 200, false},
+		{"api", HostnameOrdinal, MaxMachineID, 0, true},
+		{"node12.dc1", `^node(\d+)\.`, MaxMachineID, 12, false},
+		{"node12", `node\d+`, MaxMachineID, 0, true},
+		{"node12", `(`, MaxMachineID, 0, true},
+	} {
+		id, err := machineIDFromHostname(tc.hostname, tc.pattern, tc.maxID)
+		if (err != nil) != tc.wantErr || id != tc.want {
+			t.Errorf("machineIDFromHostname(%q, %q, %d) = %d, %v, want %d (error %t)",
+				tc.hostname, tc.pattern, tc.maxID, id, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestWithMachineIDFromEnv(t *testing.T) {
+	t.Setenv("SNOWFLAKE_TEST_MACHINE_ID", "200")
+	// 取值范围按实例的位布局校验，并覆盖 machineID 参数
+	s, err := NewSnowflake(0, 1, WithMachineIDFromEnv("SNOWFLAKE_TEST_MACHINE_ID"), WithMachineBits(8), WithDataCenterBits(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Parse(id).MachineID; got != 200 {
+		t.Errorf("machine ID = %d, want 200 from the environment", got)
+	}
+	if _, err := NewSnowflake(0, 1, WithMachineIDFromEnv("SNOWFLAKE_TEST_MACHINE_ID")); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("200 with the default layout: err = %v, want ErrMachineIDRange", err)
+	}
+}
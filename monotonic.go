@@ -0,0 +1,87 @@
+package snowflake
+
+import (
+	"sync"
+	"time"
+)
+
+// MonotonicClock 是 Clock 的可选扩展，提供独立于墙上时钟的单调时间读数
+//
+// 启用 WithMonotonicClock 时，如果时间源实现了该接口，则使用 Monotonic 计算经过的
+// 时间，测试中可以借此分别控制墙上时钟和单调时钟；否则使用 Now 返回值中携带的单调
+// 时钟读数（对系统时钟而言即 Go 运行时的单调时钟）。
+type MonotonicClock interface {
+	Clock
+	// Monotonic 返回某个固定起点以来经过的单调时间
+	Monotonic() time.Duration
+}
+
+// WithMonotonicClock 使用单调时钟推导时间戳，抵御墙上时钟的跳变
+//
+// 构造时记录一次墙上时间作为锚点，此后每个时间戳都等于锚点加上单调时钟经过的时间，
+// 因此 NTP 回拨或虚拟机暂停造成的墙上时钟跳变不会让进程内的时间戳回退或重复。
+// 代价是长时间运行后时间戳会与真实时间产生漂移，可以配合 WithReanchorInterval
+// 定期重新对齐。
+func WithMonotonicClock() Option {
+	return func(s *Snowflake) {
+		s.monotonic = true
+	}
+}
+
+// WithReanchorInterval 设置单调时钟模式下重新对齐墙上时钟的间隔，0 表示从不对齐
+//
+// 每经过 d 检查一次墙上时钟，只有当墙上时钟超前于推导出的时间时才向前对齐，
+// 永远不会让时间戳回退。仅在启用 WithMonotonicClock 时生效。
+func WithReanchorInterval(d time.Duration) Option {
+	return func(s *Snowflake) {
+		s.reanchorInterval = d
+	}
+}
+
+// monotonicClock 以墙上时钟锚点加单调时钟经过的时间作为当前时间
+type monotonicClock struct {
+	base     Clock
+	reanchor time.Duration
+	start    time.Time // 未实现 MonotonicClock 时计算单调时间的起点
+
+	mu   sync.Mutex
+	wall time.Time     // 锚点的墙上时间
+	mono time.Duration // 锚点对应的单调时间
+}
+
+func newMonotonicClock(base Clock, reanchor time.Duration) *monotonicClock {
+	c := &monotonicClock{base: base, reanchor: reanchor, start: base.Now()}
+	c.wall = c.start.Round(0)
+	c.mono = c.monotonic()
+	return c
+}
+
+// monotonic 返回当前的单调时间读数
+func (c *monotonicClock) monotonic() time.Duration {
+	if mc, ok := c.base.(MonotonicClock); ok {
+		return mc.Monotonic()
+	}
+	return c.base.Now().Sub(c.start)
+}
+
+func (c *monotonicClock) Now() time.Time {
+	m := c.monotonic()
+	if c.reanchor <= 0 {
+		return c.wall.Add(m - c.mono)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.wall.Add(m - c.mono)
+	if m-c.mono >= c.reanchor {
+		// 只有墙上时钟超前时才向前对齐，否则以推导时间作为新的锚点
+		if wall := c.base.Now().Round(0); wall.After(now) {
+			now = wall
+		}
+		c.wall, c.mono = now, m
+	}
+	return now
+}
+
+func (c *monotonicClock) Sleep(d time.Duration) { c.base.Sleep(d) }
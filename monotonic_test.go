@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// splitClock 分别控制墙上时钟和单调时钟，Sleep 同时推进两者
+type splitClock struct {
+	mu   sync.Mutex
+	wall time.Time
+	mono time.Duration
+}
+
+func (c *splitClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.wall
+}
+
+func (c *splitClock) Monotonic() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mono
+}
+
+func (c *splitClock) Sleep(d time.Duration) { c.advance(d, d) }
+
+func (c *splitClock) advance(wall, mono time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wall = c.wall.Add(wall)
+	c.mono += mono
+}
+
+func TestMonotonicClockIgnoresWallJumps(t *testing.T) {
+	clock := &splitClock{wall: testStart}
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithMonotonicClock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 墙上时钟回拨一小时，单调时钟前进 5ms
+	clock.advance(-time.Hour, 5*time.Millisecond)
+	second, err := s.Generate()
+	if err != nil {
+		t.Fatalf("Generate after a wall clock jump: %v", err)
+	}
+	if second <= first {
+		t.Errorf("ID after the wall clock jump %d, not greater than %d", second, first)
+	}
+	if got, want := s.Parse(second).Timestamp, testStart.Add(5*time.Millisecond); !got.Equal(want) {
+		t.Errorf("timestamp = %s, want %s derived from the monotonic clock", got, want)
+	}
+}
+
+func TestMonotonicClockReanchor(t *testing.T) {
+	clock := &splitClock{wall: testStart}
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithMonotonicClock(), WithReanchorInterval(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 墙上时钟超前一分钟，超过对齐间隔后向前对齐
+	clock.advance(time.Minute+time.Second, time.Second)
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Parse(id).Timestamp, testStart.Add(time.Minute+time.Second); !got.Equal(want) {
+		t.Errorf("timestamp after reanchor = %s, want %s", got, want)
+	}
+	// 墙上时钟落后时不向回对齐
+	clock.advance(-time.Hour, time.Second)
+	id, err = s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.Parse(id).Timestamp, testStart.Add(time.Minute+2*time.Second); !got.Equal(want) {
+		t.Errorf("timestamp with the wall clock behind = %s, want %s", got, want)
+	}
+}
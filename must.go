@@ -0,0 +1,29 @@
+package snowflake
+
+// MustNew 与 NewSnowflake 相同，但在参数非法时 panic
+//
+// 适用于服务启动阶段，节点 ID 来自常量或已经校验过的配置的场景。
+func MustNew(machineID int64, dataCenterID int64, opts ...Option) *Snowflake {
+	s, err := NewSnowflake(machineID, dataCenterID, opts...)
+	if err != nil {
+		panic("snowflake: " + err.Error())
+	}
+	return s
+}
+
+// MustNewSnowflake 与 MustNew 相同，命名与 NewSnowflake 对应
+//
+// panic 的信息包含超出范围的参数名称和允许的范围，例如
+// "snowflake: machine ID out of range: must be between 0 and 31, got 32"。
+func MustNewSnowflake(machineID int64, dataCenterID int64, opts ...Option) *Snowflake {
+	return MustNew(machineID, dataCenterID, opts...)
+}
+
+// MustGenerate 与 Generate 相同，但在生成失败时 panic
+func (s *Snowflake) MustGenerate() int64 {
+	id, err := s.Generate()
+	if err != nil {
+		panic("snowflake: " + err.Error())
+	}
+	return id
+}
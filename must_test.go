@@ -0,0 +1,49 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+// panicValue 调用 f 并返回其 panic 的值，没有 panic 时返回 nil
+func panicValue(f func()) (v any) {
+	defer func() { v = recover() }()
+	f()
+	return nil
+}
+
+func TestMustNew(t *testing.T) {
+	if v := panicValue(func() { MustNew(1, 1) }); v != nil {
+		t.Errorf("MustNew with valid IDs panicked: %v", v)
+	}
+	if v := panicValue(func() { MustNew(MaxMachineID+1, 1) }); v == nil {
+		t.Error("MustNew with an invalid machine ID did not panic")
+	}
+}
+
+func TestMustGenerate(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s := MustNew(1, 1, WithClock(clock))
+	if id := s.MustGenerate(); id <= 0 {
+		t.Errorf("MustGenerate() = %d, want a positive ID", id)
+	}
+	clock.Advance(-time.Millisecond)
+	if v := panicValue(func() { s.MustGenerate() }); v == nil {
+		t.Error("MustGenerate after a clock rollback did not panic")
+	}
+}
+
+func TestMustNewSnowflakePanicMessage(t *testing.T) {
+	for _, tc := range []struct {
+		machineID, dataCenterID int64
+		want                    string
+	}{
+		{32, 1, "snowflake: machine ID out of range: must be between 0 and 31, got 32"},
+		{1, -1, "snowflake: data center ID out of range: must be between 0 and 31, got -1"},
+	} {
+		v := panicValue(func() { MustNewSnowflake(tc.machineID, tc.dataCenterID) })
+		if v != tc.want {
+			t.Errorf("MustNewSnowflake(%d, %d) panicked with %v, want %q", tc.machineID, tc.dataCenterID, v, tc.want)
+		}
+	}
+}
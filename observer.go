@@ -0,0 +1,41 @@
+package snowflake
+
+// Observer 接收生成过程中的事件，可以用来对接 Prometheus 等监控系统
+//
+// 方法在生成 ID 的路径上同步调用，互斥锁实现下调用时还持有锁，因此实现必须是并发
+// 安全的，并且应当尽快返回，通常只做原子计数。
+type Observer interface {
+	// IncGenerated 在每成功生成一个 ID 时调用
+	IncGenerated()
+	// IncClockWait 在当前毫秒的序列号用尽、需要等待下一毫秒时调用
+	IncClockWait()
+	// IncClockBackwards 在检测到时钟回拨时调用，无论最终是等待、借用还是返回错误
+	IncClockBackwards()
+}
+
+// GeneratorObserver 是可以由 Observer 额外实现的接口，用于读取生成器自身的状态
+//
+// 观察者实现了该接口时，每个使用它的生成器在创建成功之后都会调用一次 ObserveGenerator，
+// 创建失败的实例和 NewShardedSnowflake 用于检查选项的临时实例不会被传入。同一个观察者
+// 被多个生成器共享时（例如分片生成器的每个分片）会收到每一个生成器。
+type GeneratorObserver interface {
+	Observer
+	// ObserveGenerator 在使用该观察者的生成器创建成功后调用
+	ObserveGenerator(s *Snowflake)
+}
+
+// WithObserver 设置生成过程的观察者，默认不做任何事，传入 nil 时保持默认值
+func WithObserver(o Observer) Option {
+	return func(s *Snowflake) {
+		if o != nil {
+			s.observer = o
+		}
+	}
+}
+
+// nopObserver 是默认的空观察者
+type nopObserver struct{}
+
+func (nopObserver) IncGenerated()      {}
+func (nopObserver) IncClockWait()      {}
+func (nopObserver) IncClockBackwards() {}
@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingObserver 统计收到的每种事件
+type countingObserver struct {
+	generated, waits, backwards atomic.Int64
+}
+
+func (o *countingObserver) IncGenerated()      { o.generated.Add(1) }
+func (o *countingObserver) IncClockWait()      { o.waits.Add(1) }
+func (o *countingObserver) IncClockBackwards() { o.backwards.Add(1) }
+
+func TestObserver(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		clock := newFakeClock(testStart)
+		o := &countingObserver{}
+		opts := []Option{WithClock(clock), WithObserver(o), WithSequenceBits(2)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 5 个 ID 需要一次等待
+		for i := 0; i < 5; i++ {
+			if _, err := s.Generate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, count, err := s.Reserve(3); err != nil || count != 3 {
+			t.Fatalf("Reserve(3) = %d, %v", count, err)
+		}
+		clock.Advance(-time.Millisecond)
+		if _, err := s.Generate(); err == nil {
+			t.Fatal("Generate after rollback succeeded")
+		}
+		if g, w, b := o.generated.Load(), o.waits.Load(), o.backwards.Load(); g != 8 || w != 1 || b != 1 {
+			t.Errorf("lock-free %t: observed generated=%d waits=%d backwards=%d, want 8, 1, 1", lockFree, g, w, b)
+		}
+	}
+}
+
+// generatorObserver 记录通过 ObserveGenerator 收到的生成器
+type generatorObserver struct {
+	countingObserver
+	sfs []*Snowflake
+}
+
+func (o *generatorObserver) ObserveGenerator(s *Snowflake) { o.sfs = append(o.sfs, s) }
+
+func TestGeneratorObserver(t *testing.T) {
+	o := &generatorObserver{}
+	s, err := NewSnowflake(1, 1, WithClock(newFakeClock(testStart)), WithObserver(o))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(o.sfs) != 1 || o.sfs[0] != s {
+		t.Fatalf("observed %v, want the created generator", o.sfs)
+	}
+	// 创建失败的实例不会被传入
+	if _, err := NewSnowflake(MaxMachineID+1, 1, WithObserver(o)); err == nil {
+		t.Fatal("NewSnowflake with an out of range machine ID succeeded")
+	}
+	// 分片生成器只传入各个分片，不传入检查选项的临时实例
+	ss, err := NewShardedSnowflake(4, 3, 1, WithClock(newFakeClock(testStart)), WithObserver(o))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	if len(o.sfs) != 4 {
+		t.Fatalf("observed %d generators, want 1 plus 3 shards", len(o.sfs))
+	}
+	for i, sf := range o.sfs[1:] {
+		if sf != ss.shards[i] {
+			t.Errorf("generator %d = %p, want shard %p", i+1, sf, ss.shards[i])
+		}
+	}
+}
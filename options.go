@@ -0,0 +1,29 @@
+package snowflake
+
+import "time"
+
+// Option 用于在创建 Snowflake 时定制其行为
+type Option func(*Snowflake)
+
+// WithTimeFunc 设置获取当前时间的函数，返回值为 Unix 毫秒时间戳
+//
+// 默认使用 time.Now().UnixMilli。主要用于在测试中注入可控的时钟，以便确定性地
+// 验证序列号溢出和时钟回拨等行为。休眠仍然使用系统时钟，需要同时控制休眠时请使用
+// WithClock。传入 nil 时保持默认值。
+func WithTimeFunc(now func() int64) Option {
+	return func(s *Snowflake) {
+		if now != nil {
+			s.clock = funcClock(now)
+		}
+	}
+}
+
+// WithEpoch 设置实例使用的基准时间，默认为 Epoch
+//
+// 基准时间会被截断到毫秒，不能晚于当前时间，也不能早到当前时间戳已经超出时间戳
+// 字段的范围。使用自定义基准时间的实例生成的 ID 需要通过 Snowflake.Decompose 拆解。
+func WithEpoch(t time.Time) Option {
+	return func(s *Snowflake) {
+		s.epoch = t.UnixMilli()
+	}
+}
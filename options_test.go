@@ -0,0 +1,73 @@
+package snowflake
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithTimeFunc(t *testing.T) {
+	var now atomic.Int64
+	now.Store(testStart.UnixMilli())
+	s, err := NewSnowflake(1, 1, WithTimeFunc(now.Load), WithSequenceBits(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := int64(0); i < 4; i++ {
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := s.Parse(id); c.Timestamp.UnixMilli() != testStart.UnixMilli() || c.Sequence != i {
+			t.Errorf("ID %d: timestamp %d, sequence %d; want %d, %d", i, c.Timestamp.UnixMilli(), c.Sequence, testStart.UnixMilli(), i)
+		}
+	}
+	// 序列号用尽后等到时间函数前进
+	time.AfterFunc(5*time.Millisecond, func() { now.Add(1) })
+	if c := s.Parse(generateWithin(t, s, 2*time.Second)); c.Timestamp.UnixMilli() != testStart.UnixMilli()+1 || c.Sequence != 0 {
+		t.Errorf("ID after exhaustion = %+v, want the next millisecond with sequence 0", c)
+	}
+}
+
+func TestWithEpoch(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, err := NewSnowflake(1, 1, WithEpoch(epoch), WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id>>TimestampShift, testStart.Sub(epoch).Milliseconds(); got != want {
+		t.Errorf("timestamp field = %d, want %d ms since the custom epoch", got, want)
+	}
+}
+
+func TestWithEpochValidation(t *testing.T) {
+	clock := newFakeClock(testStart)
+	if _, err := NewSnowflake(1, 1, WithClock(clock), WithEpoch(testStart.Add(time.Hour))); err == nil {
+		t.Error("future epoch: err = nil, want an error")
+	}
+	// 41 位毫秒时间戳约覆盖 69.7 年
+	if _, err := NewSnowflake(1, 1, WithClock(clock), WithEpoch(testStart.AddDate(-70, 0, 0))); err == nil {
+		t.Error("epoch 70 years ago: err = nil, want an error")
+	}
+
+	epoch := testStart.Add(-time.Hour)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.Decompose(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Timestamp.Equal(testStart) {
+		t.Errorf("Decompose with the instance epoch: timestamp %s, want %s", c.Timestamp, testStart)
+	}
+}
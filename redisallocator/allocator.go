@@ -0,0 +1,209 @@
+// Package redisallocator 基于 Redis 租约为 Snowflake 生成器分配数据中心 ID 和机器 ID。
+//
+// 每个 (数据中心 ID, 机器 ID) 组合对应一个 Redis 键，通过 SET NX 加过期时间抢占，
+// 抢占成功后在后台定期续期。为了不引入具体的 Redis 客户端依赖，分配器只依赖
+// Client 接口，调用方可以用任意客户端实现它。
+package redisallocator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bart-k/snowflake"
+)
+
+// Slots 是可分配的槽位总数，即数据中心 ID 与机器 ID 的全部组合
+const Slots = (snowflake.MaxDataCenterID + 1) * (snowflake.MaxMachineID + 1)
+
+// 实现 Client 时可以直接使用的 Lua 脚本，KEYS[1] 为键，ARGV[1] 为租约的值
+const (
+	// RenewScript 仅在值匹配时刷新过期时间，ARGV[2] 为毫秒过期时间，成功返回 1
+	RenewScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`
+	// ReleaseScript 仅在值匹配时删除键
+	ReleaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+)
+
+// defaultTTL 是租约默认的过期时间
+const defaultTTL = 30 * time.Second
+
+// renewDivisor 是 TTL 与续期间隔的比值
+const renewDivisor = 3
+
+// renewAttempts 是一个续期间隔内续期请求出错时最多尝试的次数
+const renewAttempts = 3
+
+// ErrPoolExhausted 表示所有槽位都已经被占用
+var ErrPoolExhausted = errors.New("redisallocator: all worker ID slots are taken")
+
+// Client 是分配器需要的最小 Redis 操作集合
+type Client interface {
+	// SetNX 仅在 key 不存在时设置 key=value 并附带过期时间，返回是否设置成功
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// CompareAndExpire 仅在 key 的值等于 value 时刷新过期时间，返回是否刷新成功，
+	// 可以使用 RenewScript 实现
+	CompareAndExpire(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// CompareAndDelete 仅在 key 的值等于 value 时删除 key，可以使用 ReleaseScript 实现
+	CompareAndDelete(ctx context.Context, key, value string) error
+}
+
+// Option 用于定制 Allocate 的行为
+type Option func(*config)
+
+type config struct {
+	ttl    time.Duration
+	onLost func(error)
+}
+
+// WithTTL 设置租约的过期时间，默认为 30 秒，续期间隔为其三分之一，因此 TTL 至少为 3ns
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// WithOnLeaseLost 设置续期失败时的回调
+//
+// 租约丢失后其他实例可能抢占同一个槽位，回调中应当立即停止使用对应的生成器。续期
+// 请求出错时先在同一个续期间隔内重试，仍然失败或者键已经不属于当前实例时才调用回调。
+// 回调最多被调用一次，之后不再续期。
+func WithOnLeaseLost(f func(error)) Option {
+	return func(c *config) {
+		c.onLost = f
+	}
+}
+
+// Allocate 在 namespace 下抢占一个空闲槽位，返回对应的机器 ID 和数据中心 ID
+//
+// 抢占成功后在后台续期，直到调用 release；release 会停止续期并删除键，可以安全地
+// 多次调用。所有槽位都被占用时返回 ErrPoolExhausted。
+func Allocate(ctx context.Context, client Client, namespace string, opts ...Option) (machineID, dataCenterID int64, release func(), err error) {
+	cfg := config{ttl: defaultTTL, onLost: func(error) {}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.ttl/renewDivisor <= 0 {
+		return 0, 0, nil, fmt.Errorf("redisallocator: TTL must be at least %dns so that the renewal interval is positive, got %s", renewDivisor, cfg.ttl)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	// 从随机位置开始扫描，减少多个实例同时启动时的冲突
+	start, err := rand.Int(rand.Reader, big.NewInt(Slots))
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("redisallocator: pick start slot: %w", err)
+	}
+	for i := int64(0); i < Slots; i++ {
+		slot := (start.Int64() + i) % Slots
+		key := namespace + ":" + strconv.FormatInt(slot, 10)
+		ok, err := client.SetNX(ctx, key, token, cfg.ttl)
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("redisallocator: claim %s: %w", key, err)
+		}
+		if ok {
+			l := newLease(client, key, token, cfg)
+			return slot & snowflake.MaxMachineID, slot >> snowflake.MachineBits, l.release, nil
+		}
+	}
+	return 0, 0, nil, ErrPoolExhausted
+}
+
+// newToken 生成用于标识租约归属的随机值
+func newToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("redisallocator: generate token: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// lease 负责续期和释放一个已经抢占的槽位
+type lease struct {
+	client   Client
+	key      string
+	token    string
+	cfg      config
+	interval time.Duration // 续期间隔
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+func newLease(client Client, key, token string, cfg config) *lease {
+	l := &lease{client: client, key: key, token: token, cfg: cfg, interval: cfg.ttl / renewDivisor, done: make(chan struct{})}
+	l.wg.Add(1)
+	go l.renewLoop()
+	return l
+}
+
+// renewLoop 每隔 TTL 的三分之一续期一次，失败时通知调用方并退出
+func (l *lease) renewLoop() {
+	defer l.wg.Done()
+	t := time.NewTicker(l.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := l.renew(); err != nil {
+				if err != errReleased {
+					l.cfg.onLost(err)
+				}
+				return
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// errReleased 表示续期过程中租约被释放
+var errReleased = errors.New("redisallocator: lease released")
+
+// renew 续期一次，请求出错时在一个续期间隔内最多尝试 renewAttempts 次
+//
+// 每次尝试的超时和重试前的等待都是续期间隔的 1/(2*renewAttempts)，全部失败时距离
+// 上次成功续期最多经过两个续期间隔，键尚未过期。键已经不属于当前实例时不再重试。
+func (l *lease) renew() error {
+	step := l.interval / (2 * renewAttempts)
+	var err error
+	for i := 0; i < renewAttempts; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(step):
+			case <-l.done:
+				return errReleased
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), max(step, time.Nanosecond))
+		var ok bool
+		ok, err = l.client.CompareAndExpire(ctx, l.key, l.token, l.cfg.ttl)
+		cancel()
+		if err == nil {
+			if !ok {
+				return fmt.Errorf("redisallocator: lease %s is no longer held", l.key)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("redisallocator: renew %s: %w", l.key, err)
+}
+
+// release 停止续期并删除键
+func (l *lease) release() {
+	l.once.Do(func() {
+		close(l.done)
+		l.wg.Wait()
+		ctx, cancel := context.WithTimeout(context.Background(), l.interval)
+		defer cancel()
+		l.client.CompareAndDelete(ctx, l.key, l.token)
+	})
+}
@@ -0,0 +1,163 @@
+package redisallocator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClient 是内存中的 Client，renewErrs 中的错误依次作为 CompareAndExpire 的结果返回
+type fakeClient struct {
+	mu        sync.Mutex
+	keys      map[string]string
+	renewErrs []error
+	renewals  int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{keys: make(map[string]string)}
+}
+
+func (c *fakeClient) SetNX(_ context.Context, key, value string, _ time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.keys[key]; ok {
+		return false, nil
+	}
+	c.keys[key] = value
+	return true, nil
+}
+
+func (c *fakeClient) CompareAndExpire(_ context.Context, key, value string, _ time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.renewals++
+	if len(c.renewErrs) > 0 {
+		err := c.renewErrs[0]
+		c.renewErrs = c.renewErrs[1:]
+		if err != nil {
+			return false, err
+		}
+	}
+	return c.keys[key] == value, nil
+}
+
+func (c *fakeClient) CompareAndDelete(_ context.Context, key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keys[key] == value {
+		delete(c.keys, key)
+	}
+	return nil
+}
+
+func (c *fakeClient) renewCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.renewals
+}
+
+func TestAllocateRejectsShortTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{-time.Second, 0, 2} {
+		if _, _, _, err := Allocate(context.Background(), newFakeClient(), "ns", WithTTL(ttl)); err == nil {
+			t.Errorf("Allocate with TTL %s: err = nil, want an error", ttl)
+		}
+	}
+}
+
+func TestRenewRetriesTransientErrors(t *testing.T) {
+	errTransient := errors.New("connection reset")
+	client := newFakeClient()
+	client.renewErrs = []error{errTransient, errTransient}
+	lost := make(chan error, 1)
+	_, _, release, err := Allocate(context.Background(), client, "ns",
+		WithTTL(30*time.Millisecond), WithOnLeaseLost(func(err error) { lost <- err }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	deadline := time.Now().Add(time.Second)
+	for client.renewCount() < 5 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case err := <-lost:
+		t.Fatalf("lease lost after transient errors: %v", err)
+	default:
+	}
+	if n := client.renewCount(); n < 5 {
+		t.Fatalf("renewed %d times, want renewal to continue after retries", n)
+	}
+}
+
+func TestRenewReportsPersistentErrors(t *testing.T) {
+	errDown := errors.New("connection refused")
+	client := newFakeClient()
+	client.renewErrs = []error{errDown, errDown, errDown}
+	lost := make(chan error, 1)
+	_, _, release, err := Allocate(context.Background(), client, "ns",
+		WithTTL(30*time.Millisecond), WithOnLeaseLost(func(err error) { lost <- err }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	select {
+	case err := <-lost:
+		if !errors.Is(err, errDown) {
+			t.Errorf("onLost error = %v, want it to wrap %v", err, errDown)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("onLost was not called")
+	}
+	if n := client.renewCount(); n != renewAttempts {
+		t.Errorf("renew attempts = %d, want %d", n, renewAttempts)
+	}
+}
+
+func TestReleaseDeletesKey(t *testing.T) {
+	client := newFakeClient()
+	_, _, release, err := Allocate(context.Background(), client, "ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	release()
+	if len(client.keys) != 0 {
+		t.Errorf("keys after release = %v, want none", client.keys)
+	}
+}
+
+func TestAllocateDistinctSlots(t *testing.T) {
+	client := newFakeClient()
+	seen := make(map[[2]int64]bool, Slots)
+	var releases []func()
+	for i := 0; i < Slots; i++ {
+		machineID, dataCenterID, release, err := Allocate(context.Background(), client, "ns")
+		if err != nil {
+			t.Fatalf("Allocate %d: %v", i, err)
+		}
+		releases = append(releases, release)
+		node := [2]int64{machineID, dataCenterID}
+		if seen[node] {
+			t.Fatalf("slot (%d, %d) allocated twice", machineID, dataCenterID)
+		}
+		seen[node] = true
+	}
+	if _, _, _, err := Allocate(context.Background(), client, "ns"); !errors.Is(err, ErrPoolExhausted) {
+		t.Errorf("Allocate with every slot taken: err = %v, want ErrPoolExhausted", err)
+	}
+	// 其他命名空间不受影响
+	_, _, release, err := Allocate(context.Background(), client, "other")
+	if err != nil {
+		t.Errorf("Allocate in another namespace: %v", err)
+	} else {
+		release()
+	}
+	for _, release := range releases {
+		release()
+	}
+}
@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reserve 预留当前毫秒内最多 n 个连续的序列号，返回起始 ID 和实际预留的数量
+//
+// base, base+1, ..., base+count-1 都是合法且唯一的 ID，调用方可以在本地递增使用而
+// 无需再次加锁。预留的 ID 与生成器此后发出的任何 ID 都不会重复：生成器的序列号被
+// 直接推进到预留区间的末尾，即使调用方没有用完预留的 ID，也不会被再次发出。
+//
+// 预留区间不会跨越毫秒，也不会跨越序列号字段的回绕点，因此当前毫秒剩余的序列号少于
+// n 时只返回剩余的部分，count 至少为 1。当前毫秒已经用尽时的等待和时钟回拨的处理
+// 与 Generate 相同。Sonyflake 布局中序列号位于机器 ID 之上，相邻的 ID 并不连续，
+// 因此 count 总是为 1。
+func (s *Snowflake) Reserve(n int) (base int64, count int, err error) {
+	if n <= 0 {
+		return 0, 0, fmt.Errorf("reserve count must be positive, got %d", n)
+	}
+	if s.sonyflake {
+		// Sonyflake 布局中相邻序列号的 ID 并不连续，只能预留一个
+		n = 1
+	}
+	if s.lockFree {
+		return s.reserveAtomic(n)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	base, err = s.nextLocked(context.Background(), true)
+	if err != nil {
+		return 0, 0, err
+	}
+	// 序列号从 sequenceStart 开始递增，回绕前最多到 maxSequence，回绕后最多到
+	// sequenceStart-1
+	limit := s.maxSequence() - s.sequence
+	if s.sequence < s.sequenceStart {
+		limit = s.sequenceStart - 1 - s.sequence
+	}
+	extra := min(int64(n-1), limit)
+	s.sequence += extra
+	s.observeReserved(extra)
+	return base, int(extra) + 1, nil
+}
+
+// reserveAtomic 是 Reserve 的无锁实现
+//
+// 先生成一个 ID 作为起点，再尝试通过 CAS 把序列号推进到预留区间的末尾；其他
+// goroutine 抢先生成时只预留起点这一个 ID。
+func (s *Snowflake) reserveAtomic(n int) (int64, int, error) {
+	base, err := s.nextAtomic(context.Background(), true)
+	if err != nil {
+		return 0, 0, err
+	}
+	l := s.layout
+	old := s.packed.Load()
+	seq := int64(old) & l.maxSequence()
+	if s.compose(int64(old>>l.SequenceBits), seq) != base {
+		return base, 1, nil
+	}
+	extra := min(int64(n-1), s.maxSequence()-seq)
+	if extra == 0 || !s.packed.CompareAndSwap(old, old+uint64(extra)) {
+		return base, 1, nil
+	}
+	s.observeReserved(extra)
+	return base, int(extra) + 1, nil
+}
+
+// observeReserved 把起点之外额外预留的 extra 个 ID 计入统计并通知观察者
+func (s *Snowflake) observeReserved(extra int64) {
+	for i := int64(0); i < extra; i++ {
+		s.countGenerated()
+	}
+}
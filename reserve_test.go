@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReserve(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		clock := newFakeClock(testStart)
+		opts := []Option{WithClock(clock), WithSequenceBits(4)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		base, count, err := s.Reserve(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 10 || s.Parse(base).Sequence != 0 {
+			t.Fatalf("lock-free %t: Reserve(10) = %d, %d, want 10 IDs from sequence 0", lockFree, base, count)
+		}
+		// 当前毫秒只剩 6 个序列号，预留被截断
+		next, count, err := s.Reserve(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next != base+10 || count != 6 {
+			t.Errorf("lock-free %t: second Reserve(10) = %d, %d, want %d, 6", lockFree, next, count, base+10)
+		}
+		// 预留的 ID 不会再次发出
+		id := generateWithin(t, s, 2*time.Second)
+		if c := s.Parse(id); !c.Timestamp.Equal(testStart.Add(time.Millisecond)) || c.Sequence != 0 {
+			t.Errorf("lock-free %t: ID after the reservations = %+v, want the next millisecond", lockFree, c)
+		}
+		if got := s.Stats().TotalGenerated; got != 17 {
+			t.Errorf("lock-free %t: TotalGenerated = %d, want 17", lockFree, got)
+		}
+		if _, _, err := s.Reserve(0); err == nil {
+			t.Errorf("lock-free %t: Reserve(0): err = nil, want an error", lockFree)
+		}
+	}
+}
+
+func TestReserveRandomStartWraps(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithSequenceBits(4), WithRandomSequenceStart())
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[int64]bool{}
+	for len(seen) < 16 {
+		base, count, err := s.Reserve(16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for id := base; id < base+int64(count); id++ {
+			c := s.Parse(id)
+			if !c.Timestamp.Equal(testStart) || seen[c.Sequence] {
+				t.Fatalf("reserved ID %+v repeats a sequence or leaves the millisecond", c)
+			}
+			seen[c.Sequence] = true
+		}
+	}
+}
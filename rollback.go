@@ -0,0 +1,53 @@
+package snowflake
+
+import "time"
+
+// RollbackPolicy 决定检测到时钟回拨时 Generate 的行为
+type RollbackPolicy int
+
+const (
+	// RollbackError 立即返回包装了 ErrClockMovedBackwards 的错误（默认）
+	RollbackError RollbackPolicy = iota
+	// RollbackWait 在回拨幅度不超过最大等待时间时休眠，直到时钟追上上次的时间戳；
+	// 超过最大等待时间则返回错误
+	RollbackWait
+	// RollbackBorrow 继续沿用上次的时间戳并递增序列号，直到序列号即将溢出时返回错误；
+	// 配合 WithMaxBackwardDrift 可以限制允许借用的回拨幅度
+	RollbackBorrow
+)
+
+// defaultMaxRollbackWait 是 RollbackWait 策略默认的最大等待时间
+const defaultMaxRollbackWait = 5 * time.Millisecond
+
+// WithRollbackPolicy 设置时钟回拨的处理策略，默认为 RollbackError
+func WithRollbackPolicy(p RollbackPolicy) Option {
+	return func(s *Snowflake) {
+		s.rollbackPolicy = p
+	}
+}
+
+// WithMaxRollbackWait 设置 RollbackWait 策略下允许等待的最大回拨幅度，默认为 5ms
+func WithMaxRollbackWait(d time.Duration) Option {
+	return func(s *Snowflake) {
+		s.maxRollbackWait = d
+	}
+}
+
+// WithMaxBackwardDrift 容忍不超过 d 的时钟回拨，等价于使用 RollbackBorrow 策略并限制
+// 回拨幅度
+//
+// NTP 校时常常会把时钟向回调整几毫秒。回拨幅度在 d 以内时继续沿用上次的时间戳并递增
+// 序列号，生成的 ID 依然唯一且单调递增；回拨超过 d，或借用期间序列号即将溢出时，返回
+// 包装了 ErrClockMovedBackwards 的错误。
+func WithMaxBackwardDrift(d time.Duration) Option {
+	return func(s *Snowflake) {
+		s.rollbackPolicy = RollbackBorrow
+		s.maxBackwardDrift = d
+	}
+}
+
+// withinDrift 判断回拨的时间单位数是否在 RollbackBorrow 允许的幅度以内，未设置
+// WithMaxBackwardDrift 时不限制幅度
+func (s *Snowflake) withinDrift(backwards int64) bool {
+	return s.maxBackwardDrift <= 0 || time.Duration(backwards)*s.unit <= s.maxBackwardDrift
+}
@@ -0,0 +1,105 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRollbackPolicies(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		opts     []Option
+		rollback time.Duration
+		wantErr  bool
+		wantTime time.Time // 回拨后生成的 ID 的时间
+	}{
+		{"error", nil, 2 * time.Millisecond, true, time.Time{}},
+		{"wait", []Option{WithRollbackPolicy(RollbackWait)}, 2 * time.Millisecond, false, testStart},
+		{"wait too long", []Option{WithRollbackPolicy(RollbackWait), WithMaxRollbackWait(time.Millisecond)}, 2 * time.Millisecond, true, time.Time{}},
+		{"borrow", []Option{WithRollbackPolicy(RollbackBorrow)}, 2 * time.Millisecond, false, testStart},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock(testStart)
+			s, err := NewSnowflake(1, 1, append([]Option{WithClock(clock)}, tc.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			first, err := s.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			clock.Advance(-tc.rollback)
+			id, err := s.Generate()
+			if tc.wantErr {
+				if !errors.Is(err, ErrClockMovedBackwards) {
+					t.Fatalf("err = %v, want ErrClockMovedBackwards", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id <= first {
+				t.Errorf("ID after rollback %d, not greater than %d", id, first)
+			}
+			if got := Parse(id).Timestamp; !got.Equal(tc.wantTime) {
+				t.Errorf("timestamp after rollback = %s, want %s", got, tc.wantTime)
+			}
+		})
+	}
+}
+
+func TestRollbackBorrowExhausted(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithRollbackPolicy(RollbackBorrow), WithSequenceBits(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(-time.Millisecond)
+	// 借用上次的时间戳，序列号用尽后返回错误
+	for i := 0; i < 3; i++ {
+		if _, err := s.Generate(); err != nil {
+			t.Fatalf("borrowed ID %d: %v", i, err)
+		}
+	}
+	if _, err := s.Generate(); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("err after borrowing the whole sequence = %v, want ErrClockMovedBackwards", err)
+	}
+}
+
+func TestMaxBackwardDrift(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		clock := newFakeClock(testStart)
+		opts := []Option{WithClock(clock), WithMaxBackwardDrift(5 * time.Millisecond)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 回拨 5ms 以内沿用上次的时间戳
+		clock.Advance(-5 * time.Millisecond)
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatalf("lock-free %t: rollback within the drift: %v", lockFree, err)
+		}
+		if c := s.Parse(id); id <= first || !c.Timestamp.Equal(testStart) || c.Sequence != 1 {
+			t.Errorf("lock-free %t: borrowed ID %+v, want %s with sequence 1", lockFree, c, testStart)
+		}
+		// 超过 5ms 返回错误
+		clock.Advance(-time.Millisecond)
+		var cbe *ClockBackwardsError
+		if _, err := s.Generate(); !errors.As(err, &cbe) || cbe.Backwards != 6*time.Millisecond {
+			t.Errorf("lock-free %t: rollback beyond the drift: err = %v, want a 6ms ClockBackwardsError", lockFree, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package snowflake
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// SaveState 将最后一次生成 ID 的时间和序列号写入 w
+//
+// 时间以绝对的 Unix 纳秒记录，与实例的基准时间无关。写入的内容可以通过 LoadState
+// 恢复到之后创建的实例，防止进程在时钟回拨后重启时重复使用已经用过的时间戳。格式与
+// WithStateFile 的状态文件相同，写入文件后也可以直接交给 NewSnowflakeWithStateFile。
+func (s *Snowflake) SaveState(w io.Writer) error {
+	last, seq := s.snapshot()
+	if _, err := w.Write(appendState(nil, s.timeOf(last).UnixNano(), seq)); err != nil {
+		return fmt.Errorf("save snowflake state: %w", err)
+	}
+	return nil
+}
+
+// LoadState 从 r 读取 SaveState 写入的状态，确保之后生成的 ID 不会与保存前的重复
+//
+// 如果保存的时间领先于当前时钟，LoadState 会一直休眠到时钟追上保存的时间，而不受
+// 回拨策略的限制；时钟落后很多时可能长时间阻塞，调用方可以先检查状态文件的内容。
+// 追上后如果仍处于保存时的时间单位，从保存的序列号之后继续生成。应当在生成任何 ID
+// 之前调用。
+func (s *Snowflake) LoadState(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("load snowflake state: %w", err)
+	}
+	ns, seq, err := parseState(data)
+	if err != nil {
+		return fmt.Errorf("load snowflake state: %w", err)
+	}
+	if seq > s.maxSequence() {
+		return fmt.Errorf("load snowflake state: sequence %d exceeds %d", seq, s.maxSequence())
+	}
+
+	mark := (ns - s.epoch*int64(time.Millisecond)) / int64(s.unit)
+	if mark < 0 {
+		return nil
+	}
+	for backwards := mark - s.tick(); backwards > 0; backwards = mark - s.tick() {
+		s.clock.Sleep(time.Duration(backwards) * s.unit)
+	}
+	if s.randomSequence || seq < 0 {
+		// 随机起点下或序列号未知时无法确定已经用过哪些序列号，把整个时间单位视为已经用尽
+		seq = s.maxSequence()
+	}
+	if last, _ := s.snapshot(); mark >= last {
+		s.restore(mark, seq)
+	}
+	return nil
+}
+
+// NewSnowflakeWithStateFile 创建一个把高水位持久化到 path 的 Snowflake 实例
+//
+// 等价于附加 WithStateFile(path) 选项，区别在于启动时文件中记录的时间领先于当前
+// 时钟时总是等待时钟追上，而不是按照回拨策略返回错误。使用完毕后必须调用 Close。
+func NewSnowflakeWithStateFile(path string, machineID int64, dataCenterID int64, opts ...Option) (*Snowflake, error) {
+	return NewSnowflake(machineID, dataCenterID, append(opts, WithStateFile(path), withStateWait())...)
+}
+
+// withStateWait 让状态文件的高水位领先当前时钟时总是等待
+func withStateWait() Option {
+	return func(s *Snowflake) {
+		s.stateWait = true
+	}
+}
@@ -0,0 +1,37 @@
+package snowflake
+
+import "math/rand/v2"
+
+// WithRandomSequenceStart 让每个新毫秒的序列号从 [0, maxSequence] 中的随机值开始
+//
+// 默认情况下每毫秒的序列号都从 0 开始，相邻 ID 的低位可以直接看出签发速率。启用后
+// 序列号从随机起点递增，并在字段宽度内回绕，回到起点时视为当前毫秒已经用尽，因此
+// 唯一性保证不变。代价是同一毫秒内的 ID 不再保证按生成顺序递增，只有跨毫秒仍然
+// 有序。该选项不能与 WithLockFree 同时使用。
+func WithRandomSequenceStart() Option {
+	return func(s *Snowflake) {
+		s.randomSequence = true
+	}
+}
+
+// WithRandomSource 启用 WithRandomSequenceStart，并使用 src 产生每毫秒的起始序列号
+//
+// 传入固定种子的随机数源（例如 rand.NewPCG(1, 2)）可以让测试中的序列号复现。src
+// 只在持有该实例的互斥锁时使用，因此只被一个实例使用时不需要自行保证并发安全；但这把
+// 锁不保护其他实例，同一个 src 或同一个选项值不能用于多个实例，否则会发生数据竞争。
+// NewShardedSnowflake 和 NewPool 因此拒绝该选项，需要随机起点时使用
+// WithRandomSequenceStart。
+func WithRandomSource(src rand.Source) Option {
+	return func(s *Snowflake) {
+		s.randomSequence = true
+		s.sequenceRand = rand.New(src)
+	}
+}
+
+// randomStart 返回新毫秒的随机起始序列号，调用方必须持有 s.mu
+func (s *Snowflake) randomStart() int64 {
+	if s.sequenceRand != nil {
+		return s.sequenceRand.Int64N(s.maxSequence() + 1)
+	}
+	return rand.Int64N(s.maxSequence() + 1)
+}
@@ -0,0 +1,89 @@
+package snowflake
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestRandomSequenceStart(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithSequenceBits(4), WithRandomSequenceStart())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonZeroStarts := 0
+	for ms := 0; ms < 50; ms++ {
+		// 上一毫秒用尽后，第一次生成通过时钟等待到下一毫秒
+		tick := testStart.Add(time.Duration(ms) * time.Millisecond)
+		seen := map[int64]bool{}
+		for i := 0; i < 16; i++ {
+			c := s.Parse(generateWithin(t, s, 2*time.Second))
+			if !c.Timestamp.Equal(tick) {
+				t.Fatalf("ID %d of millisecond %d has timestamp %s, want %s", i, ms, c.Timestamp, tick)
+			}
+			if seen[c.Sequence] {
+				t.Fatalf("duplicate sequence %d in millisecond %d", c.Sequence, ms)
+			}
+			if i == 0 && c.Sequence != 0 {
+				nonZeroStarts++
+			}
+			seen[c.Sequence] = true
+		}
+	}
+	if nonZeroStarts == 0 {
+		t.Error("all 50 milliseconds started at sequence 0")
+	}
+
+	if _, err := NewSnowflake(1, 1, WithRandomSequenceStart(), WithLockFree()); err == nil {
+		t.Error("random sequence start with the lock-free generator: err = nil, want an error")
+	}
+}
+
+func TestWithRandomSource(t *testing.T) {
+	newSeeded := func() *Snowflake {
+		s, err := NewSnowflake(1, 1, WithClock(newFakeClock(testStart)), WithRandomSource(rand.NewPCG(1, 2)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	a, b := newSeeded(), newSeeded()
+	seen := make(map[int64]bool, MaxSequence+1)
+	for i := 0; i <= MaxSequence; i++ {
+		x, err := a.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, err := b.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != y {
+			t.Fatalf("ID %d: %d != %d with the same seed", i, x, y)
+		}
+		c := a.Parse(x)
+		if !c.Timestamp.Equal(testStart) {
+			t.Fatalf("ID %d has timestamp %s, want all 4096 IDs in %s", i, c.Timestamp, testStart)
+		}
+		if i == 0 && c.Sequence == 0 {
+			t.Error("seeded source started at sequence 0")
+		}
+		if seen[c.Sequence] {
+			t.Fatalf("duplicate sequence %d", c.Sequence)
+		}
+		seen[c.Sequence] = true
+	}
+	if len(seen) != MaxSequence+1 {
+		t.Errorf("got %d distinct sequences, want %d", len(seen), MaxSequence+1)
+	}
+
+	// 序列号回到起点后当前毫秒用尽，下一个 ID 进入下一毫秒
+	id, err := a.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := a.Parse(id); !c.Timestamp.Equal(testStart.Add(time.Millisecond)) {
+		t.Errorf("ID 4097 has timestamp %s, want the next millisecond", c.Timestamp)
+	}
+}
@@ -0,0 +1,43 @@
+package snowflake
+
+// SetIDs 在运行时修改实例的机器 ID 和数据中心 ID
+//
+// 取值范围按照实例的位布局校验，不合法时返回 *RangeError 且不做任何修改。修改在持有
+// 互斥锁的情况下进行，已经在进行中的 Generate 要么完全使用旧值，要么完全使用新值。
+// lastTimestamp 保持不变；新的节点 ID 小于旧值时，当前时间单位被标记为已经用尽，下一个
+// ID 进入新的时间单位，因此本进程生成的 ID 依然单调递增。
+//
+// SetIDs 只保证本进程内的正确性：切换到的新 ID 如果仍被其他主机使用，双方会生成重复
+// 的 ID，调用方需要通过外部协调（例如先让旧主机停止生成）避免冲突。
+func (s *Snowflake) SetIDs(machineID int64, dataCenterID int64) error {
+	if err := s.checkIDs(machineID, dataCenterID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node := s.nodeBits(machineID, dataCenterID)
+	if node < s.node.Load() && !s.sonyflake {
+		// 节点字段位于序列号之上，同一时间单位内变小会让之后的 ID 小于已经发出的 ID；
+		// Sonyflake 的节点字段在最低位，不受影响
+		s.exhaustCurrentTick()
+	}
+	s.machineID, s.dataCenterID = machineID, dataCenterID
+	s.node.Store(node)
+	return nil
+}
+
+// exhaustCurrentTick 把最后一次生成 ID 的时间单位标记为已经用尽，调用方必须持有 s.mu
+func (s *Snowflake) exhaustCurrentTick() {
+	if s.lockFree {
+		maxSeq := uint64(s.maxSequence())
+		for {
+			old := s.packed.Load()
+			if s.packed.CompareAndSwap(old, old|maxSeq) {
+				return
+			}
+		}
+	}
+	// 序列号回到起点的前一个值即表示用尽
+	s.sequence = (s.sequenceStart - 1) & s.maxSequence()
+}
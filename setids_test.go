@@ -0,0 +1,87 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSetIDsKeepsMonotonic(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"mutex", nil},
+		{"lock-free", []Option{WithLockFree()}},
+		{"random start", []Option{WithRandomSequenceStart()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock(testStart)
+			s, err := NewSnowflake(5, 5, append([]Option{WithClock(clock)}, tc.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before, err := s.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.SetIDs(3, 3); err != nil {
+				t.Fatal(err)
+			}
+			after, err := s.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if after <= before {
+				t.Fatalf("ID after SetIDs = %d, want greater than %d", after, before)
+			}
+			c := Parse(after)
+			if c.MachineID != 3 || c.DataCenterID != 3 {
+				t.Errorf("node IDs after SetIDs = (%d, %d), want (3, 3)", c.MachineID, c.DataCenterID)
+			}
+		})
+	}
+}
+
+func TestSetIDsRange(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetIDs(MaxMachineID+1, 1); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("SetIDs machine out of range: err = %v, want ErrMachineIDRange", err)
+	}
+	if err := s.SetIDs(1, -1); !errors.Is(err, ErrDataCenterIDRange) {
+		t.Errorf("SetIDs data center out of range: err = %v, want ErrDataCenterIDRange", err)
+	}
+	id, _ := s.Generate()
+	if c := Parse(id); c.MachineID != 1 || c.DataCenterID != 1 {
+		t.Errorf("failed SetIDs changed node IDs to (%d, %d)", c.MachineID, c.DataCenterID)
+	}
+}
+
+func TestSetIDsConcurrent(t *testing.T) {
+	s, err := NewAtomicSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				if _, err := s.Generate(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		if err := s.SetIDs(int64(i%32), int64(i%7)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
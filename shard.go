@@ -0,0 +1,54 @@
+package snowflake
+
+import "context"
+
+// GenerateForShard 生成一个在节点字段中携带逻辑分片 ID 的唯一 ID
+//
+// 与 Instagram 的方案相同，数据中心 ID 和机器 ID 两个字段合并后用来存放 shardID（例如
+// userID % 1024），这样可以直接从主键读出数据所在的分片。分片的位宽由实例的位布局
+// 决定，例如 Layout{MachineBits: 12, SequenceBits: 10} 提供 4096 个分片。shardID 超出
+// 范围时返回包装了 ErrShardRange 的 *RangeError。
+//
+// 所有分片共享实例的滚动序列号：同一时间单位内为不同分片生成的 ID 序列号也不同，因此
+// 无论分片是否相同都不会冲突，代价是单个实例每个时间单位的总生成量不随分片数增加。
+// 不同进程为同一分片生成的 ID 之间没有这个保证，需要确保每个分片同一时刻只由一个实例
+// 负责。
+func (s *Snowflake) GenerateForShard(shardID int64) (int64, error) {
+	if maxShard := s.maxShard(); shardID < 0 || shardID > maxShard {
+		return 0, &RangeError{Err: ErrShardRange, Value: shardID, Max: maxShard}
+	}
+
+	var id int64
+	var err error
+	if s.lockFree {
+		id, err = s.nextAtomic(context.Background(), true)
+	} else {
+		s.mu.Lock()
+		id, err = s.nextLocked(context.Background(), true)
+		s.mu.Unlock()
+	}
+	if err != nil {
+		return 0, err
+	}
+	shift := s.shardShift()
+	return id&^(s.maxShard()<<shift) | shardID<<shift, nil
+}
+
+// ShardOf 返回 GenerateForShard 写入 ID 的分片 ID，即按照实例的位布局合并后的数据中心
+// ID 和机器 ID
+func (s *Snowflake) ShardOf(id int64) int64 {
+	return (id >> s.shardShift()) & s.maxShard()
+}
+
+// maxShard 返回分片 ID 的最大值，即数据中心 ID 和机器 ID 两个字段合并后的范围
+func (s *Snowflake) maxShard() int64 {
+	return -1 ^ (-1 << (s.layout.DataCenterBits + s.layout.MachineBits))
+}
+
+// shardShift 返回分片 ID 在 ID 中的偏移，Sonyflake 模式下机器 ID 位于最低位
+func (s *Snowflake) shardShift() int {
+	if s.sonyflake {
+		return 0
+	}
+	return s.layout.machineShift()
+}
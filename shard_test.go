@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateForShard(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		opts := []Option{WithClock(newFakeClock(testStart))}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflakeWithLayout(Layout{MachineBits: 12, SequenceBits: 10}, 5, 0, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[int64]bool)
+		for i, shard := range []int64{0, 1, 4095, 1, 1234, 0} {
+			id, err := s.GenerateForShard(shard)
+			if err != nil {
+				t.Fatalf("GenerateForShard(%d): %v", shard, err)
+			}
+			if seen[id] {
+				t.Fatalf("lock-free %t: duplicate ID %d", lockFree, id)
+			}
+			seen[id] = true
+			if got := s.ShardOf(id); got != shard {
+				t.Errorf("lock-free %t: ShardOf(%d) = %d, want %d", lockFree, id, got, shard)
+			}
+			if c := s.Parse(id); c.Sequence != int64(i) || c.MachineID != shard {
+				t.Errorf("lock-free %t: Parse(%d) = %+v, want machine %d sequence %d", lockFree, id, c, shard, i)
+			}
+		}
+	}
+}
+
+func TestGenerateForShardDefaultLayout(t *testing.T) {
+	s, err := NewSnowflake(1, 1, WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 默认布局下分片 ID 占据数据中心 ID 和机器 ID 两个字段，与 worker ID 相同
+	id, err := s.GenerateForShard(MaxWorkerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := s.Parse(id); c.DataCenterID != MaxDataCenterID || c.MachineID != MaxMachineID || c.WorkerID() != MaxWorkerID {
+		t.Errorf("Parse = %+v, want all node bits set", c)
+	}
+}
+
+func TestGenerateForShardSonyflake(t *testing.T) {
+	s, err := NewSonyflakeCompat(1, WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.GenerateForShard(0xbeef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.ShardOf(id); got != 0xbeef || ParseSonyflake(id).MachineID != 0xbeef {
+		t.Errorf("ShardOf(%d) = %#x, want 0xbeef in the machine ID field", id, got)
+	}
+}
+
+func TestGenerateForShardRange(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, shard := range []int64{-1, MaxWorkerID + 1} {
+		_, err := s.GenerateForShard(shard)
+		var re *RangeError
+		if !errors.Is(err, ErrShardRange) || !errors.As(err, &re) || re.Max != MaxWorkerID {
+			t.Errorf("GenerateForShard(%d): err = %v, want a RangeError wrapping ErrShardRange", shard, err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ShardedSnowflake 持有一段连续的机器 ID，并将 Generate 调用轮流分派给各个分片
+//
+// 每个分片是一个独立的 Snowflake 实例，占用不同的机器 ID，因此各分片生成的 ID
+// 互不冲突，整体吞吐量约为 shards*(MaxSequence+1) 个每毫秒。单个分片内的 ID 严格
+// 递增，但不同分片之间的 ID 不保证按调用顺序递增。
+type ShardedSnowflake struct {
+	shards []*Snowflake
+	next   atomic.Uint64
+}
+
+// NewShardedSnowflake 创建一个占用机器 ID [baseMachineID, baseMachineID+shards) 的分片生成器
+//
+// 整个机器 ID 区间必须落在实例位布局允许的范围内，opts 会应用到每一个分片。推导机器 ID
+// 的 WithMachineIDFromHostname、WithMachineIDFromEnv 会让所有分片使用同一个机器 ID，
+// WithStateFile 会让所有分片写同一个文件，WithRandomSource 会让所有分片在各自的锁下并发
+// 使用同一个随机数源，因此这些选项会被拒绝。WithRandomSequenceStart 使用并发安全的全局
+// 随机数源，可以正常使用。使用完毕后应当调用 Close。
+func NewShardedSnowflake(baseMachineID int64, shards int, dataCenterID int64, opts ...Option) (*ShardedSnowflake, error) {
+	if shards <= 0 {
+		return nil, fmt.Errorf("shard count must be positive, got %d", shards)
+	}
+	probe := &Snowflake{}
+	for _, opt := range opts {
+		opt(probe)
+	}
+	if probe.machineIDSource != nil {
+		return nil, errors.New("sharded generator assigns machine IDs itself and does not support machine ID derivation options")
+	}
+	if probe.statePath != "" {
+		return nil, errors.New("sharded generator does not support a shared state file")
+	}
+	if probe.sequenceRand != nil {
+		return nil, errors.New("sharded generator does not support a shared random source, use WithRandomSequenceStart instead")
+	}
+
+	ss := &ShardedSnowflake{shards: make([]*Snowflake, 0, shards)}
+	for i := 0; i < shards; i++ {
+		sf, err := NewSnowflake(baseMachineID+int64(i), dataCenterID, opts...)
+		if err != nil {
+			ss.Close()
+			return nil, fmt.Errorf("create shard %d with machine ID %d: %w", i, baseMachineID+int64(i), err)
+		}
+		ss.shards = append(ss.shards, sf)
+	}
+	return ss, nil
+}
+
+// Close 关闭所有分片，之后 Generate 返回 ErrClosed，可以安全地多次调用
+func (ss *ShardedSnowflake) Close() error {
+	var errs []error
+	for _, sf := range ss.shards {
+		if err := sf.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Generate 轮流使用各个分片生成唯一 ID
+func (ss *ShardedSnowflake) Generate() (int64, error) {
+	i := (ss.next.Add(1) - 1) % uint64(len(ss.shards))
+	return ss.shards[i].Generate()
+}
+
+// Shards 返回分片数量
+func (ss *ShardedSnowflake) Shards() int {
+	return len(ss.shards)
+}
+
+// Pool 是 ShardedSnowflake 的别名，用于在多核环境下分散单个序列号的竞争
+type Pool = ShardedSnowflake
+
+// NewPool 创建一个包含 shards 个分片的 Pool，分片依次占用机器 ID 0 到 shards-1
+//
+// 每个分片独占一个机器 ID，因此分片数不能超过机器 ID 字段的容量：默认 5 位布局下
+// 每个数据中心最多 32 个分片，可以通过 WithMachineBits 调整。占用整个机器 ID 空间意味着
+// 同一数据中心内不能再有其他节点使用这些机器 ID。
+func NewPool(shards int, dataCenterID int64, opts ...Option) (*Pool, error) {
+	return NewShardedSnowflake(0, shards, dataCenterID, opts...)
+}
@@ -0,0 +1,170 @@
+package snowflake
+
+import (
+	"errors"
+	"math/rand/v2"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestShardedUnique(t *testing.T) {
+	ss, err := NewShardedSnowflake(4, 8, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+
+	const goroutines, perGoroutine = 16, 5000
+	var mu sync.Mutex
+	seen := make(map[int64]bool, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]int64, 0, perGoroutine)
+			for i := 0; i < perGoroutine; i++ {
+				id, err := ss.Generate()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids = append(ids, id)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("duplicate ID %d", id)
+				}
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	machines := map[int64]bool{}
+	for id := range seen {
+		c := Parse(id)
+		if c.DataCenterID != 2 || c.MachineID < 4 || c.MachineID >= 12 {
+			t.Fatalf("ID %d has node (%d, %d), want data center 2 and machine in [4, 12)", id, c.DataCenterID, c.MachineID)
+		}
+		machines[c.MachineID] = true
+	}
+	if len(machines) != 8 {
+		t.Errorf("IDs used %d machine IDs, want 8", len(machines))
+	}
+}
+
+func TestShardedRandomSequenceStart(t *testing.T) {
+	// 分片在各自的锁下并发取随机起点，需要配合 -race 运行
+	ss, err := NewShardedSnowflake(0, 4, 1, WithRandomSequenceStart(), WithSequenceBits(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+
+	const goroutines, perGoroutine = 8, 200
+	ids := make(chan int64, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				id, err := ss.Generate()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[int64]bool, goroutines*perGoroutine)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestShardedRejectsSharedOptions(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"env":        WithMachineIDFromEnv("SNOWFLAKE_TEST_MACHINE_ID"),
+		"hostname":   WithMachineIDFromHostname(""),
+		"state file": WithStateFile(filepath.Join(t.TempDir(), "state")),
+		"random":     WithRandomSource(rand.NewPCG(1, 2)),
+	} {
+		if ss, err := NewShardedSnowflake(0, 2, 1, opt); err == nil {
+			ss.Close()
+			t.Errorf("%s: NewShardedSnowflake succeeded, want error", name)
+		}
+	}
+}
+
+func TestShardedRange(t *testing.T) {
+	if _, err := NewShardedSnowflake(30, 4, 1); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("machine IDs beyond the layout: err = %v, want ErrMachineIDRange", err)
+	}
+	if _, err := NewPool(0, 1); err == nil {
+		t.Error("NewPool(0) succeeded, want error")
+	}
+}
+
+func TestShardedClose(t *testing.T) {
+	ss, err := NewPool(4, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	for i := 0; i < ss.Shards(); i++ {
+		if _, err := ss.Generate(); !errors.Is(err, ErrClosed) {
+			t.Errorf("Generate after Close: err = %v, want ErrClosed", err)
+		}
+	}
+}
+
+func TestNewPool(t *testing.T) {
+	p, err := NewPool(MaxMachineID+1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if p.Shards() != MaxMachineID+1 {
+		t.Errorf("Shards() = %d, want %d", p.Shards(), MaxMachineID+1)
+	}
+	machines := map[int64]bool{}
+	for i := 0; i < 4*p.Shards(); i++ {
+		id, err := p.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := Parse(id)
+		if c.DataCenterID != 3 {
+			t.Fatalf("ID %d has data center %d, want 3", id, c.DataCenterID)
+		}
+		machines[c.MachineID] = true
+	}
+	if len(machines) != p.Shards() {
+		t.Errorf("IDs used %d machine IDs, want all %d", len(machines), p.Shards())
+	}
+
+	if _, err := NewPool(MaxMachineID+2, 3); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("more shards than machine IDs: err = %v, want ErrMachineIDRange", err)
+	}
+	wide, err := NewPool(64, 0, WithMachineBits(6), WithDataCenterBits(4))
+	if err != nil {
+		t.Fatalf("64 shards with 6 machine bits: %v", err)
+	}
+	wide.Close()
+}
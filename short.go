@@ -0,0 +1,35 @@
+package snowflake
+
+// 53 位短 ID 模式的参数
+const (
+	// MaxSafeID 是 JavaScript Number.MAX_SAFE_INTEGER，短 ID 总是不超过该值
+	MaxSafeID = 1<<53 - 1
+	// ShortMachineBits 是短 ID 中机器 ID 的位宽
+	ShortMachineBits = 4
+	// ShortSequenceBits 是短 ID 中序列号的位宽
+	ShortSequenceBits = 8
+)
+
+// NewShortSnowflake 创建一个生成 53 位短 ID 的实例，ID 可以被 JavaScript 的 Number
+// 精确表示
+//
+// 短 ID 从高到低依次为 41 位毫秒时间戳、4 位机器 ID 和 8 位序列号，没有数据中心 ID：
+//
+//	id = timestamp<<12 | machineID<<8 | sequence
+//
+// 时间戳相对实例的基准时间（默认为 Epoch），可以覆盖约 69 年；单个实例每毫秒最多
+// 生成 256 个 ID。machineID 必须在 [0, 15] 范围内。时间戳超出 41 位时 Generate 返回
+// ErrEpochOverflow，因此生成的 ID 总是小于 2^53。生成的 ID 需要通过
+// Snowflake.Decompose 拆解。位布局和 WithUnsigned 会被忽略，其他选项照常生效。
+func NewShortSnowflake(machineID int64, opts ...Option) (*Snowflake, error) {
+	return NewSnowflake(machineID, 0, append(opts, withShort())...)
+}
+
+// withShort 设置 53 位短 ID 的位布局
+func withShort() Option {
+	return func(s *Snowflake) {
+		s.layout = Layout{MachineBits: ShortMachineBits, SequenceBits: ShortSequenceBits}
+		s.unsigned = false
+		s.idBits = 53
+	}
+}
@@ -0,0 +1,64 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewShortSnowflake(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewShortSnowflake(15, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prev int64
+	for i := 0; i < 300; i++ {
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= prev || id > MaxSafeID {
+			t.Fatalf("ID %d: %d is not increasing or exceeds MaxSafeID", i, id)
+		}
+		prev = id
+	}
+	// 每毫秒最多 256 个 ID，第 300 个落在下一毫秒
+	c, err := s.Decompose(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := testStart.Add(time.Millisecond); !c.Timestamp.Equal(want) || c.MachineID != 15 || c.Sequence != 300-256-1 {
+		t.Errorf("Decompose = %+v, want machine 15, sequence 43 at %s", c, want)
+	}
+	ms := testStart.UnixMilli() + 1 - Epoch
+	if want := ms<<12 | 15<<8 | 43; prev != want {
+		t.Errorf("last ID = %d, want timestamp<<12 | machineID<<8 | sequence = %d", prev, want)
+	}
+}
+
+func TestShortSnowflakeOverflow(t *testing.T) {
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := newFakeClock(epoch.Add((1<<41 - 1) * time.Millisecond))
+	s, err := NewShortSnowflake(1, WithClock(clock), WithEpoch(epoch), WithUnsigned())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id > MaxSafeID {
+		t.Errorf("last ID %d exceeds MaxSafeID", id)
+	}
+	clock.Advance(time.Millisecond)
+	if _, err := s.Generate(); !errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("err = %v, want ErrEpochOverflow", err)
+	}
+}
+
+func TestNewShortSnowflakeRange(t *testing.T) {
+	if _, err := NewShortSnowflake(16); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("NewShortSnowflake(16): err = %v, want ErrMachineIDRange", err)
+	}
+}
@@ -0,0 +1,397 @@
+// Package snowflake 实现了 Twitter Snowflake 风格的分布式唯一 ID 生成器。
+//
+// 生成的 ID 是一个 63 位正整数，从高位到低位依次为：
+//
+//	41 位毫秒时间戳（相对 Epoch） | 5 位数据中心 ID | 5 位机器 ID | 12 位序列号
+//
+// 同一毫秒内每个节点最多可以生成 MaxSequence+1 个 ID。
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Epoch 毫秒级时间戳偏移量，基准时间可以是任意设定的起始时间
+	Epoch          = 1629980400000 // 例如：2021-08-26 00:00:00 UTC
+	MachineBits    = 5             // 机器ID长度
+	DataCenterBits = 5             // 数据中心ID长度
+	SequenceBits   = 12            // 序列号长度
+)
+
+const (
+	MaxMachineID    = -1 ^ (-1 << MachineBits)    // 最大机器 ID，5 位机器 ID，最大值为 31
+	MaxDataCenterID = -1 ^ (-1 << DataCenterBits) // 最大数据中心 ID，5 位数据中心 ID，最大值为 31
+	MaxSequence     = -1 ^ (-1 << SequenceBits)   // 最大序列号，12 位序列号，最大值为 4095
+)
+
+const (
+	MachineShift    = SequenceBits                                // 机器 ID 偏移
+	DataCenterShift = SequenceBits + MachineBits                  // 数据中心 ID 偏移
+	TimestampShift  = SequenceBits + MachineBits + DataCenterBits // 时间戳偏移
+)
+
+// Snowflake struct 用于管理 ID 生成
+//
+// Snowflake 可以安全地被多个 goroutine 并发使用。
+type Snowflake struct {
+	mu            sync.Mutex
+	machineID     int64
+	dataCenterID  int64
+	node          atomic.Int64 // 移位后的数据中心 ID 和机器 ID，compose 直接与之按位或
+	sequence      int64
+	lastTimestamp int64
+	epoch         int64         // 实例使用的 Unix 毫秒基准时间
+	clock         Clock         // 时间源
+	unit          time.Duration // 时间戳的时间单位
+	layout        Layout        // 实例使用的位布局
+
+	// machineIDSource 在创建实例时推导机器 ID，参数为位布局允许的最大机器 ID
+	machineIDSource func(maxID int64) (int64, error)
+
+	rollbackPolicy   RollbackPolicy // 时钟回拨处理策略
+	maxRollbackWait  time.Duration  // RollbackWait 策略的最大等待时间
+	maxBackwardDrift time.Duration  // RollbackBorrow 策略允许借用的最大回拨幅度，0 表示不限制
+	waitStrategy     WaitStrategy   // 序列号用尽时的等待方式
+
+	sonyflake      bool       // 是否使用 Sonyflake 的字段顺序，序列号位于机器 ID 之上
+	tagBits        int        // 序列号字段高位分配给标签的位数
+	randomSequence bool       // 每毫秒是否从随机序列号开始
+	sequenceStart  int64      // 当前毫秒的起始序列号
+	sequenceRand   *rand.Rand // 随机起始序列号的随机数源，为 nil 时使用全局随机数源
+
+	monotonic        bool          // 是否使用单调时钟推导时间戳
+	reanchorInterval time.Duration // 单调时钟模式下重新对齐墙上时钟的间隔
+
+	unsigned bool // 是否把符号位也用于时间戳
+	idBits   int  // ID 使用的总位数，时间戳占用其中剩余的高位
+
+	backfill map[int64]int64 // GenerateAt 在每个回填时间戳上最后使用的序列号
+	started  int64           // 实例创建时的时间戳，实时生成的 ID 从这里开始
+
+	lockFree bool          // 是否使用无锁实现
+	packed   atomic.Uint64 // 无锁实现下打包的 lastTimestamp 和 sequence
+
+	observer  Observer      // 生成过程的观察者，默认为空实现
+	generated atomic.Uint64 // 已经生成的 ID 总数
+	exhausted atomic.Uint64 // 序列号用尽后等待下一时间单位的次数
+	rollbacks atomic.Uint64 // 检测到时钟回拨的次数
+
+	statePath     string        // 状态文件路径
+	stateInterval time.Duration // 状态文件写入间隔
+	stateWarn     func(error)   // 状态文件读写问题的回调
+	stateWait     bool          // 状态文件的高水位领先当前时钟时是否总是等待
+	state         *stateFile    // 状态文件的后台写入，未启用时为 nil
+	allocator     IDAllocator   // 机器 ID 的分配器，Close 时归还，未使用时为 nil
+	closed        atomic.Bool   // 是否已经调用过 Close
+	closeOnce     sync.Once
+}
+
+// NewSnowflake 创建一个新的 Snowflake 实例
+//
+// 默认位布局下 machineID 必须在 [0, MaxMachineID] 范围内，dataCenterID 必须在
+// [0, MaxDataCenterID] 范围内，否则返回 *RangeError，可以分别通过 errors.Is 与
+// ErrInvalidMachineID 和 ErrInvalidDataCenterID 比较。可以通过 opts 定制实例的行为。
+func NewSnowflake(machineID int64, dataCenterID int64, opts ...Option) (*Snowflake, error) {
+	s := &Snowflake{
+		machineID:    machineID,
+		dataCenterID: dataCenterID,
+		epoch:        Epoch,
+		clock:        realClock{},
+		unit:         time.Millisecond,
+		layout:       defaultLayout,
+
+		maxRollbackWait: defaultMaxRollbackWait,
+		waitStrategy:    WaitSleep,
+		observer:        nopObserver{},
+		idBits:          63,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	maxBits := maxLayoutBits
+	if s.sonyflake {
+		maxBits = sonyflakeLayoutBits
+	}
+	if err := s.layout.validate(maxBits); err != nil {
+		return nil, err
+	}
+	if s.sonyflake && (s.tagBits != 0 || s.layout.DataCenterBits != 0) {
+		return nil, fmt.Errorf("sonyflake layout does not support tag or data center bits")
+	}
+	if s.tagBits < 0 || s.tagBits >= s.layout.SequenceBits {
+		return nil, fmt.Errorf("tag bits must be between 0 and %d, got %d", s.layout.SequenceBits-1, s.tagBits)
+	}
+	if s.lockFree && s.randomSequence {
+		return nil, fmt.Errorf("random sequence start is not supported by the lock-free generator")
+	}
+	if s.machineIDSource != nil {
+		id, err := s.machineIDSource(s.layout.maxMachineID())
+		if err != nil {
+			return nil, err
+		}
+		machineID, s.machineID = id, id
+	}
+	if err := s.checkIDs(machineID, dataCenterID); err != nil {
+		return nil, err
+	}
+	s.node.Store(s.nodeBits(machineID, dataCenterID))
+	if s.monotonic {
+		s.clock = newMonotonicClock(s.clock, s.reanchorInterval)
+	}
+	if s.unit <= 0 {
+		return nil, fmt.Errorf("time unit must be positive, got %s", s.unit)
+	}
+	if ts := s.tick(); s.epoch > s.clock.Now().UnixMilli() {
+		return nil, fmt.Errorf("epoch %s must not be in the future", time.UnixMilli(s.epoch).UTC().Format(time.RFC3339Nano))
+	} else if ts > s.maxTimestamp() {
+		return nil, fmt.Errorf("epoch %s is too old, timestamp would overflow %d bits",
+			time.UnixMilli(s.epoch).UTC().Format(time.RFC3339Nano), s.timestampBits())
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	s.started = s.tick()
+	if s.statePath != "" {
+		if err := s.openState(); err != nil {
+			return nil, err
+		}
+	}
+	if o, ok := s.observer.(GeneratorObserver); ok {
+		o.ObserveGenerator(s)
+	}
+	return s, nil
+}
+
+// Generate 生成唯一的 Snowflake ID
+//
+// 同一实例生成的 ID 严格递增。当前毫秒的序列号用尽时，Generate 会等待到下一毫秒；
+// 如果检测到系统时钟回拨，则按照 RollbackPolicy 处理，默认返回包装了
+// ErrClockMovedBackwards 的错误，而不是生成可能重复的 ID。
+func (s *Snowflake) Generate() (int64, error) {
+	if s.lockFree {
+		return s.nextAtomic(context.Background(), true)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nextLocked(context.Background(), true)
+}
+
+// GenerateContext 与 Generate 相同，但在等待时钟前进时响应 ctx 的取消
+//
+// 如果在等待下一毫秒或等待时钟回拨恢复（RollbackWait）期间 ctx 被取消或超过截止
+// 时间，返回 ctx.Err()。可取消的 ctx 总是使用定时器等待而不是空转，与
+// WaitStrategy 的设置无关。
+func (s *Snowflake) GenerateContext(ctx context.Context) (int64, error) {
+	if s.lockFree {
+		return s.nextAtomic(ctx, true)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nextLocked(ctx, true)
+}
+
+// GenerateN 一次性生成 n 个唯一 ID
+//
+// 整个过程只获取一次锁，当前毫秒的序列号用尽时自动进入下一毫秒，返回的 ID 严格递增。
+// 如果中途出错，返回已经生成的部分 ID 和错误。
+func (s *Snowflake) GenerateN(n int) ([]int64, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", n)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, n)
+	for len(ids) < n {
+		id, err := s.nextLocked(context.Background(), true)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Fill 在一次加锁中生成 len(dst) 个唯一 ID 写入 dst，返回写入的数量
+//
+// 与 GenerateN 的区别是使用调用方提供的缓冲区，不分配内存，适合复用缓冲区的热点路径。
+// 当前毫秒的序列号用尽时自动进入下一毫秒，写入的 ID 严格递增。如果中途出错，dst 的
+// 前 n 个元素为已经生成的 ID。
+func (s *Snowflake) Fill(dst []int64) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for n < len(dst) {
+		if dst[n], err = s.nextLocked(context.Background(), true); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// MaxBatchSize 是 GenerateBatch 单次允许生成的最大 ID 数量
+const MaxBatchSize = 1 << 20
+
+// GenerateBatch 一次性生成 n 个唯一 ID，n 必须在 [1, MaxBatchSize] 范围内
+//
+// 与 GenerateN 一样只获取一次锁，返回的 ID 严格递增。当前毫秒的序列号用尽时，
+// GenerateBatch 会释放锁并休眠到下一毫秒，而不是在持有锁的情况下空转，期间其他
+// goroutine 可以继续调用 Stats 等方法。如果中途出错，返回已经生成的部分 ID 和错误。
+func (s *Snowflake) GenerateBatch(n int) ([]int64, error) {
+	if n <= 0 || n > MaxBatchSize {
+		return nil, fmt.Errorf("batch size must be between 1 and %d, got %d", MaxBatchSize, n)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, n)
+	for len(ids) < n {
+		if s.sequenceExhausted() && s.tick() == s.lastTimestamp {
+			// 当前毫秒已经用尽，释放锁后休眠到下一毫秒
+			s.countClockWait()
+			s.mu.Unlock()
+			s.clock.Sleep(s.untilNextTick())
+			s.mu.Lock()
+			continue
+		}
+		id, err := s.nextLocked(context.Background(), true)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// nextLocked 生成下一个 ID，调用方必须持有 s.mu
+//
+// 等待时钟前进的过程中如果 ctx 被取消，返回 ctx.Err()。block 为 false 时从不等待，
+// 需要等待下一毫秒时返回 ErrSequenceExhausted，需要等待时钟回拨恢复时返回回拨错误。
+func (s *Snowflake) nextLocked(ctx context.Context, block bool) (int64, error) {
+	if s.lockFree {
+		return s.nextAtomic(ctx, block)
+	}
+	if s.closed.Load() {
+		return 0, ErrClosed
+	}
+	for {
+		// 获取当前时间戳（毫秒）
+		timestamp := s.tick()
+		if err := s.checkTimestamp(timestamp); err != nil {
+			return 0, err
+		}
+
+		// 时钟回拨时按策略处理，避免与已经发出的 ID 冲突
+		if timestamp < s.lastTimestamp {
+			backwards := s.lastTimestamp - timestamp
+			s.countClockBackwards()
+			switch s.rollbackPolicy {
+			case RollbackWait:
+				if wait := time.Duration(backwards) * s.unit; block && wait <= s.maxRollbackWait {
+					// 休眠期间释放锁，避免在持有锁的情况下空转
+					s.mu.Unlock()
+					err := s.sleepContext(ctx, wait)
+					s.mu.Lock()
+					if err != nil {
+						return 0, err
+					}
+					continue
+				}
+			case RollbackBorrow:
+				// 借用上次的时间戳，直到序列号即将溢出或回拨超出允许的漂移
+				if !s.sequenceExhausted() && s.withinDrift(backwards) {
+					s.sequence = (s.sequence + 1) & s.maxSequence()
+					s.countGenerated()
+					return s.compose(s.lastTimestamp, s.sequence), nil
+				}
+			}
+			return 0, &ClockBackwardsError{Backwards: time.Duration(backwards) * s.unit}
+		}
+
+		// 检查时间戳变化，处理序列号溢出
+		if timestamp == s.lastTimestamp {
+			if !s.sequenceExhausted() {
+				s.sequence = (s.sequence + 1) & s.maxSequence()
+				s.countGenerated()
+				return s.compose(timestamp, s.sequence), nil
+			}
+			// 如果序列号溢出，则等待下一个毫秒后重新获取时间戳
+			if !block {
+				return 0, ErrSequenceExhausted
+			}
+			s.countClockWait()
+			if err := s.waitNextTick(ctx); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		// 进入新的毫秒，重置序列号并更新最后时间戳
+		s.sequence = 0
+		if s.randomSequence {
+			s.sequence = s.randomStart()
+		}
+		s.sequenceStart = s.sequence
+		s.lastTimestamp = timestamp
+
+		s.countGenerated()
+		return s.compose(timestamp, s.sequence), nil
+	}
+}
+
+// sequenceExhausted 判断当前毫秒的序列号是否已经用尽，调用方必须持有 s.mu
+//
+// 序列号从 sequenceStart 开始递增并在字段宽度内回绕，回到起点即表示用尽。
+func (s *Snowflake) sequenceExhausted() bool {
+	return (s.sequence+1)&s.maxSequence() == s.sequenceStart
+}
+
+// checkTimestamp 检查时间戳是否仍能放入时间戳字段
+func (s *Snowflake) checkTimestamp(timestamp int64) error {
+	if maxTS := s.maxTimestamp(); timestamp > maxTS {
+		return fmt.Errorf("%w: %d ms since epoch does not fit in %d bits", ErrEpochOverflow, timestamp, s.timestampBits())
+	}
+	return nil
+}
+
+// compose 使用给定的时间戳和序列号构建唯一 ID
+func (s *Snowflake) compose(timestamp int64, sequence int64) int64 {
+	l := s.layout
+	if s.sonyflake {
+		// Sonyflake 的序列号位于机器 ID 之上
+		return (timestamp << l.timestampShift()) | (sequence << l.MachineBits) | s.node.Load()
+	}
+	return (timestamp << l.timestampShift()) | s.node.Load() | sequence
+}
+
+// checkIDs 按照实例的位布局检查机器 ID 和数据中心 ID 的范围
+func (s *Snowflake) checkIDs(machineID int64, dataCenterID int64) error {
+	if maxID := s.layout.maxMachineID(); machineID < 0 || machineID > maxID {
+		return &RangeError{Err: ErrMachineIDRange, Value: machineID, Max: maxID}
+	}
+	if maxID := s.layout.maxDataCenterID(); dataCenterID < 0 || dataCenterID > maxID {
+		return &RangeError{Err: ErrDataCenterIDRange, Value: dataCenterID, Max: maxID}
+	}
+	return nil
+}
+
+// nodeBits 返回机器 ID 和数据中心 ID 移位到各自字段后的值
+func (s *Snowflake) nodeBits(machineID int64, dataCenterID int64) int64 {
+	if s.sonyflake {
+		return machineID
+	}
+	return dataCenterID<<s.layout.dataCenterShift() | machineID<<s.layout.machineShift()
+}
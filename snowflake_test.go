@@ -0,0 +1,382 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGenerateIncreasing(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last int64
+	for i := 0; i < 10000; i++ {
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("ID %d = %d, not greater than previous %d", i, id, last)
+		}
+		last = id
+	}
+}
+
+func TestNewSnowflakeIDRange(t *testing.T) {
+	for _, tc := range []struct {
+		machineID, dataCenterID int64
+		want                    error
+	}{
+		{0, 0, nil},
+		{MaxMachineID, MaxDataCenterID, nil},
+		{-1, 0, ErrMachineIDRange},
+		{MaxMachineID + 1, 0, ErrMachineIDRange},
+		{0, -1, ErrDataCenterIDRange},
+		{0, MaxDataCenterID + 1, ErrDataCenterIDRange},
+	} {
+		_, err := NewSnowflake(tc.machineID, tc.dataCenterID)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("NewSnowflake(%d, %d): err = %v, want %v", tc.machineID, tc.dataCenterID, err, tc.want)
+		}
+	}
+}
+
+func TestExportedLayoutConstants(t *testing.T) {
+	if TimestampShift+41 != 63 {
+		t.Errorf("TimestampShift = %d, want 41 timestamp bits below the sign bit", TimestampShift)
+	}
+	s, err := NewSnowflake(7, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := id >> MachineShift & MaxMachineID; got != 7 {
+		t.Errorf("machine ID from shift constants = %d, want 7", got)
+	}
+	if got := id >> DataCenterShift & MaxDataCenterID; got != 3 {
+		t.Errorf("data center ID from shift constants = %d, want 3", got)
+	}
+	if got := id & MaxSequence; got != 0 {
+		t.Errorf("first sequence = %d, want 0", got)
+	}
+}
+
+func TestClockRollbackRejected(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(-2 * time.Millisecond)
+	if id, err := s.Generate(); err == nil {
+		t.Fatalf("Generate after rollback = %d, want an error", id)
+	}
+	// 时钟追上之后恢复生成
+	clock.Advance(3 * time.Millisecond)
+	if _, err := s.Generate(); err != nil {
+		t.Errorf("Generate after the clock caught up: %v", err)
+	}
+}
+
+func TestClockRollbackSentinel(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(-time.Millisecond)
+	if _, err := s.Generate(); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("Generate after rollback: err = %v, want ErrClockMovedBackwards", err)
+	}
+}
+
+func TestGenerateN(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 超过一毫秒的序列号容量，需要跨越多个毫秒
+	ids, err := s.GenerateN(3*(MaxSequence+1) + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3*(MaxSequence+1)+1 {
+		t.Fatalf("GenerateN returned %d IDs, want %d", len(ids), 3*(MaxSequence+1)+1)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not increasing at %d: %d after %d", i, ids[i], ids[i-1])
+		}
+	}
+	if ids, err := s.GenerateN(0); err != nil || len(ids) != 0 {
+		t.Errorf("GenerateN(0) = %v, %v, want no IDs", ids, err)
+	}
+	if _, err := s.GenerateN(-1); err == nil {
+		t.Error("GenerateN(-1): err = nil, want an error")
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Generate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkShardedGenerate(b *testing.B) {
+	ss, err := NewShardedSnowflake(0, 4, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ss.Close()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ss.Generate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkShardedGenerateParallel(b *testing.B) {
+	ss, err := NewShardedSnowflake(0, 4, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ss.Close()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := ss.Generate(); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkGenerateN(b *testing.B) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.GenerateN(1000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestGenerateContextCanceled(t *testing.T) {
+	for _, w := range []WaitStrategy{WaitSleep, WaitSpin} {
+		// 时钟停止不前，序列号用尽后只能等待 ctx 取消
+		frozen := testStart.UnixMilli()
+		s, err := NewSnowflake(1, 1, WithTimeFunc(func() int64 { return frozen }), WithWaitStrategy(w), WithSequenceBits(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := s.GenerateContext(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err = s.GenerateContext(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("strategy %d: err = %v, want context.DeadlineExceeded", w, err)
+		}
+	}
+}
+
+func TestGenerateBatch(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithSequenceBits(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := s.GenerateBatch(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not increasing at %d: %d after %d", i, ids[i], ids[i-1])
+		}
+	}
+	// 每毫秒 4 个 ID，10 个 ID 需要两次等待
+	if got, want := clock.Now(), testStart.Add(2*time.Millisecond); !got.Equal(want) {
+		t.Errorf("clock after batch = %s, want %s", got, want)
+	}
+	if n := s.Stats().SequenceExhaustedCount; n != 2 {
+		t.Errorf("SequenceExhaustedCount = %d, want 2", n)
+	}
+	for _, n := range []int{0, -1, MaxBatchSize + 1} {
+		if _, err := s.GenerateBatch(n); err == nil {
+			t.Errorf("GenerateBatch(%d): err = nil, want an error", n)
+		}
+	}
+}
+
+func BenchmarkGenerateBatch(b *testing.B) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.GenerateBatch(1000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEpochOverflow(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		// 时钟停在时间戳字段能表示的最后一毫秒
+		clock := newFakeClock(epoch.Add(time.Duration(defaultLayout.maxTimestamp()) * time.Millisecond))
+		opts := []Option{WithEpoch(epoch), WithClock(clock)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatalf("lock-free %t: Generate in the last millisecond: %v", lockFree, err)
+		}
+		if id <= 0 {
+			t.Errorf("lock-free %t: ID in the last millisecond = %d, want positive", lockFree, id)
+		}
+		clock.Advance(time.Millisecond)
+		if _, err := s.Generate(); !errors.Is(err, ErrEpochOverflow) {
+			t.Errorf("lock-free %t: err = %v, want ErrEpochOverflow", lockFree, err)
+		}
+	}
+}
+
+func TestGenerateContextCancelsRollbackWait(t *testing.T) {
+	var offset atomic.Int64
+	now := func() int64 { return time.Now().UnixMilli() + offset.Load() }
+	s, err := NewSnowflake(1, 1, WithTimeFunc(now), WithRollbackPolicy(RollbackWait), WithMaxRollbackWait(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	// 时钟回拨 30 秒，等待期间 ctx 的截止时间应当立即打断休眠
+	offset.Store(-30 * time.Second.Milliseconds())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := s.GenerateContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GenerateContext returned after %s, want shortly after the deadline", elapsed)
+	}
+}
+
+func TestFill(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		opts := []Option{WithClock(newFakeClock(testStart))}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst := make([]int64, 2*(MaxSequence+1)+1)
+		n, err := s.Fill(dst)
+		if err != nil || n != len(dst) {
+			t.Fatalf("lock-free %t: Fill = %d, %v, want %d, nil", lockFree, n, err, len(dst))
+		}
+		for i := 1; i < n; i++ {
+			if dst[i] <= dst[i-1] {
+				t.Fatalf("lock-free %t: ID %d = %d is not greater than %d", lockFree, i, dst[i], dst[i-1])
+			}
+		}
+		if n, err := s.Fill(nil); n != 0 || err != nil {
+			t.Errorf("lock-free %t: Fill(nil) = %d, %v, want 0, nil", lockFree, n, err)
+		}
+	}
+}
+
+func TestFillPartial(t *testing.T) {
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := newFakeClock(epoch.Add(time.Duration(defaultLayout.maxTimestamp()) * time.Millisecond))
+	s, err := NewSnowflake(1, 1, WithEpoch(epoch), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 最后一毫秒只能生成 MaxSequence+1 个 ID，之后时间戳溢出
+	dst := make([]int64, MaxSequence+10)
+	n, err := s.Fill(dst)
+	if n != MaxSequence+1 || !errors.Is(err, ErrEpochOverflow) {
+		t.Errorf("Fill = %d, %v, want %d, ErrEpochOverflow", n, err, MaxSequence+1)
+	}
+	if dst[n-1] == 0 || dst[n] != 0 {
+		t.Errorf("Fill wrote dst[%d] = %d and dst[%d] = %d, want only the first %d elements written", n-1, dst[n-1], n, dst[n], n)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.Fill(dst); n != 0 || !errors.Is(err, ErrClosed) {
+		t.Errorf("Fill after Close = %d, %v, want 0, ErrClosed", n, err)
+	}
+}
+
+func TestFillAllocs(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := make([]int64, 100)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := s.Fill(dst); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Fill allocates %v times per call, want 0", allocs)
+	}
+}
+
+func BenchmarkFill(b *testing.B) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	dst := make([]int64, 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Fill(dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
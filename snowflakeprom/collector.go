@@ -0,0 +1,156 @@
+// Package snowflakeprom 将 Snowflake 生成器的运行指标导出为 Prometheus 指标。
+//
+// 核心包不依赖 Prometheus，只有导入本包的程序才会引入该依赖：
+//
+//	sf, err := snowflake.NewSnowflake(1, 1, snowflakeprom.WithMetrics(prometheus.DefaultRegisterer))
+//
+// 计数器在生成路径上只做一次原子加法，指标在被抓取时才生成，不会给 Generate 增加
+// 可观测的延迟。
+package snowflakeprom
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/bart-k/snowflake"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	generatedDesc = prometheus.NewDesc(
+		"snowflake_ids_generated_total",
+		"Number of IDs generated.",
+		nil, nil,
+	)
+	exhaustedDesc = prometheus.NewDesc(
+		"snowflake_sequence_exhausted_total",
+		"Number of times the per-millisecond sequence was exhausted and generation waited for the next millisecond.",
+		nil, nil,
+	)
+	backwardsDesc = prometheus.NewDesc(
+		"snowflake_clock_backwards_total",
+		"Number of detected clock regressions.",
+		nil, nil,
+	)
+	utilizationDesc = prometheus.NewDesc(
+		"snowflake_sequence_utilization_ratio",
+		"Fraction of the sequence space used in the current time unit of the generator clock, 0 if no ID was generated in it.",
+		nil, nil,
+	)
+)
+
+// Collector 同时实现 snowflake.GeneratorObserver 和 prometheus.Collector
+//
+// 一个 Collector 可以关联多个生成器，例如分片生成器的全部分片，此时导出的是所有生成器
+// 的合计值。需要分别观察多个生成器时可以为每个生成器创建一个 Collector，并通过
+// prometheus.WrapRegistererWith 为它们添加不同的标签。
+type Collector struct {
+	generated atomic.Uint64
+	exhausted atomic.Uint64
+	backwards atomic.Uint64
+
+	mu  sync.Mutex
+	sfs []*snowflake.Snowflake // 已经创建成功的关联生成器，只追加
+}
+
+// NewCollector 创建一个 Collector，reg 不为 nil 时立即注册到 reg
+//
+// 注册只在这里发生一次，之后可以通过 Attach 把同一个 Collector 关联到任意多个生成器。
+// reg 为 nil 时不注册，由调用方自行注册。注册失败时返回 reg.Register 的错误。
+func NewCollector(reg prometheus.Registerer) (*Collector, error) {
+	c := &Collector{}
+	if reg != nil {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Attach 返回一个 snowflake.Option，把 c 设置为生成器的观察者
+//
+// 生成器创建成功后会通过 ObserveGenerator 把自己交给 c，用于计算序列号利用率。
+func (c *Collector) Attach() snowflake.Option {
+	return snowflake.WithObserver(c)
+}
+
+var (
+	metricsMu sync.Mutex
+	metrics   = map[prometheus.Registerer]*Collector{} // WithMetrics 在每个 Registerer 上注册的 Collector
+)
+
+// WithMetrics 返回一个 snowflake.Option，把注册在 reg 上的 Collector 关联到正在创建的生成器
+//
+// 对同一个 reg 第一次调用时创建 Collector 并注册，之后的调用复用这个 Collector 而不会
+// 重复注册，因此多个生成器可以各自使用 WithMetrics(reg)，导出的指标是它们的合计值。
+// reg 必须是可比较的类型，*prometheus.Registry 等指针类型都满足。第一次注册失败（例如
+// reg 上已经注册了导出同名指标的其他 Collector）时，与 MustRegister 一样会 panic。
+func WithMetrics(reg prometheus.Registerer) snowflake.Option {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	c, ok := metrics[reg]
+	if !ok {
+		var err error
+		if c, err = NewCollector(reg); err != nil {
+			panic(err)
+		}
+		metrics[reg] = c
+	}
+	return c.Attach()
+}
+
+// ObserveGenerator 实现 snowflake.GeneratorObserver，记录 s 用于计算序列号利用率
+func (c *Collector) ObserveGenerator(s *snowflake.Snowflake) {
+	c.mu.Lock()
+	c.sfs = append(c.sfs, s)
+	c.mu.Unlock()
+}
+
+// IncGenerated 实现 snowflake.Observer
+func (c *Collector) IncGenerated() { c.generated.Add(1) }
+
+// IncClockWait 实现 snowflake.Observer
+func (c *Collector) IncClockWait() { c.exhausted.Add(1) }
+
+// IncClockBackwards 实现 snowflake.Observer
+func (c *Collector) IncClockBackwards() { c.backwards.Add(1) }
+
+// Describe 实现 prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- generatedDesc
+	ch <- exhaustedDesc
+	ch <- backwardsDesc
+	ch <- utilizationDesc
+}
+
+// Collect 实现 prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(generatedDesc, prometheus.CounterValue, float64(c.generated.Load()))
+	ch <- prometheus.MustNewConstMetric(exhaustedDesc, prometheus.CounterValue, float64(c.exhausted.Load()))
+	ch <- prometheus.MustNewConstMetric(backwardsDesc, prometheus.CounterValue, float64(c.backwards.Load()))
+	ch <- prometheus.MustNewConstMetric(utilizationDesc, prometheus.GaugeValue, c.utilization())
+}
+
+// utilization 返回关联生成器当前时间单位的序列号利用率，即各生成器在当前时间单位已用的
+// 序列号之和除以序列号空间之和，尚未关联生成器时返回 0
+//
+// 是否处于同一时间单位按照每个生成器自己的时钟和时间单位判断，而不是墙上时间和固定的
+// 一毫秒，注入的时钟和非毫秒的时间单位下同样准确。借用未来时间戳时最后的时间戳晚于
+// 当前时间，此时的序列号同样属于正在使用的时间单位。
+func (c *Collector) utilization() float64 {
+	c.mu.Lock()
+	sfs := c.sfs
+	c.mu.Unlock()
+	var used, capacity int64
+	for _, sf := range sfs {
+		st := sf.Stats()
+		capacity += st.MaxSequence + 1
+		if !st.LastGeneratedAt.Before(st.Now) {
+			used += st.CurrentSequence + 1
+		}
+	}
+	if capacity == 0 {
+		return 0
+	}
+	return float64(used) / float64(capacity)
+}
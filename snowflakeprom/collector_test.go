@@ -0,0 +1,200 @@
+package snowflakeprom
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bart-k/snowflake"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fakeClock 是只在测试中手动推进的时钟
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) { c.Advance(d) }
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestUtilizationUsesGeneratorClock(t *testing.T) {
+	// 注入的时钟远离墙上时间，时间单位为 10ms
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	c, err := NewCollector(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sf, err := snowflake.NewSnowflake(1, 1,
+		snowflake.WithEpoch(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		snowflake.WithClock(clock),
+		snowflake.WithTimeUnit(10*time.Millisecond),
+		c.Attach())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.utilization(); got != 0 {
+		t.Errorf("utilization before generating = %v, want 0", got)
+	}
+	for i := 0; i < 1024; i++ {
+		if _, err := sf.Generate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := c.utilization(); got != 0.25 {
+		t.Errorf("utilization = %v, want 0.25", got)
+	}
+
+	clock.Advance(5 * time.Millisecond)
+	if got := c.utilization(); got != 0.25 {
+		t.Errorf("utilization within the same unit = %v, want 0.25", got)
+	}
+	clock.Advance(5 * time.Millisecond)
+	if got := c.utilization(); got != 0 {
+		t.Errorf("utilization in the next unit = %v, want 0", got)
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	sf, err := snowflake.NewSnowflake(1, 1, snowflake.WithClock(clock), snowflake.WithSequenceBits(1), WithMetrics(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := sf.Generate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock.Advance(-time.Millisecond)
+	if _, err := sf.Generate(); err == nil {
+		t.Fatal("Generate after rollback succeeded")
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]float64{}
+	for _, mf := range families {
+		m := mf.GetMetric()[0]
+		if c := m.GetCounter(); c != nil {
+			got[mf.GetName()] = c.GetValue()
+		} else {
+			got[mf.GetName()] = m.GetGauge().GetValue()
+		}
+	}
+	want := map[string]float64{
+		"snowflake_ids_generated_total":      3,
+		"snowflake_sequence_exhausted_total": 1,
+		"snowflake_clock_backwards_total":    1,
+		// 回拨后最后的时间戳仍然领先于时钟，其序列号 0 属于正在使用的时间单位
+		"snowflake_sequence_utilization_ratio": 0.5,
+	}
+	for name, v := range want {
+		if got[name] != v {
+			t.Errorf("%s = %v, want %v", name, got[name], v)
+		}
+	}
+
+	// 同一个 Registerer 上再次使用 WithMetrics 复用已经注册的 Collector，指标合计
+	other, err := snowflake.NewSnowflake(2, 1, snowflake.WithClock(clock), WithMetrics(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	families, err = reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range families {
+		if mf.GetName() == "snowflake_ids_generated_total" && mf.GetMetric()[0].GetCounter().GetValue() != 4 {
+			t.Errorf("snowflake_ids_generated_total after a second WithMetrics = %v, want 4", mf.GetMetric()[0].GetCounter().GetValue())
+		}
+	}
+	var are prometheus.AlreadyRegisteredError
+	if _, err := NewCollector(reg); !errors.As(err, &are) {
+		t.Errorf("NewCollector on a registry with a collector: err = %v, want AlreadyRegisteredError", err)
+	}
+}
+
+func TestCollectorAggregatesShards(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	c, err := NewCollector(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := snowflake.NewShardedSnowflake(0, 4, 1, snowflake.WithClock(clock), snowflake.WithSequenceBits(2), c.Attach())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	// 创建失败的生成器不会被记录
+	if _, err := snowflake.NewSnowflake(1<<20, 1, c.Attach()); err == nil {
+		t.Fatal("NewSnowflake with an out of range machine ID succeeded")
+	}
+	if len(c.sfs) != 4 {
+		t.Fatalf("collector observes %d generators, want the 4 shards", len(c.sfs))
+	}
+	// 每个分片的序列号空间为 4，轮流分派后每个分片各用掉 1 个和 2 个
+	for i, want := range []float64{0.25, 0.5} {
+		for j := 0; j < 4; j++ {
+			if _, err := ss.Generate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := c.utilization(); got != want {
+			t.Errorf("utilization after %d rounds = %v, want %v", i+1, got, want)
+		}
+	}
+	if got := c.generated.Load(); got != 8 {
+		t.Errorf("generated = %d, want 8 across all shards", got)
+	}
+}
+
+// BenchmarkGenerate 比较关联 Collector 前后 Generate 的开销
+func BenchmarkGenerate(b *testing.B) {
+	for _, bc := range []struct {
+		name    string
+		collect bool
+	}{
+		{"without collector", false},
+		{"with collector", true},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var opts []snowflake.Option
+			if bc.collect {
+				c, err := NewCollector(nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				opts = append(opts, c.Attach())
+			}
+			sf, err := snowflake.NewSnowflake(1, 1, opts...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := sf.Generate(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package snowflaketest_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bart-k/snowflake"
+	"github.com/bart-k/snowflake/snowflaketest"
+)
+
+// newOrderID 是依赖 snowflake.Generator 的被测代码
+func newOrderID(gen snowflake.Generator) (string, error) {
+	id, err := gen.Generate()
+	if err != nil {
+		return "", fmt.Errorf("create order: %w", err)
+	}
+	return fmt.Sprintf("order-%d", id), nil
+}
+
+func ExampleNewFakeGenerator() {
+	gen := snowflaketest.NewFakeGenerator(100)
+	a, _ := newOrderID(gen)
+	b, _ := newOrderID(gen)
+	fmt.Println(a, b, gen.Requested())
+	// Output: order-100 order-101 2
+}
+
+func ExampleNewScriptedGenerator() {
+	gen := snowflaketest.NewScriptedGenerator(42)
+	fmt.Println(newOrderID(gen))
+	_, err := newOrderID(gen)
+	fmt.Println(errors.Is(err, snowflaketest.ErrScriptExhausted))
+	// Output:
+	// order-42 <nil>
+	// true
+}
+
+func ExampleFakeGenerator_FailOn() {
+	gen := snowflaketest.NewFakeGenerator(1)
+	gen.FailOn(1, errors.New("unavailable"))
+	_, err := newOrderID(gen)
+	fmt.Println(err)
+	fmt.Println(newOrderID(gen))
+	// Output:
+	// create order: unavailable
+	// order-1 <nil>
+}
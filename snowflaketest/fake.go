@@ -0,0 +1,113 @@
+// Package snowflaketest 提供用于测试 snowflake.Generator 使用方的辅助实现。
+//
+// 被测代码依赖 snowflake.Generator 接口时，可以在测试中注入 FakeGenerator，断言确定的
+// ID 和调用次数：
+//
+//	gen := snowflaketest.NewFakeGenerator(100)
+//	svc := NewService(gen) // 生产环境中传入 *snowflake.Snowflake
+//	order, _ := svc.CreateOrder()
+//	// order.ID == 100，gen.Requested() == 1
+package snowflaketest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bart-k/snowflake"
+)
+
+// ErrScriptExhausted 表示 NewScriptedGenerator 预设的 ID 已经全部返回
+var ErrScriptExhausted = errors.New("scripted IDs exhausted")
+
+// FakeGenerator 是返回确定 ID 的 snowflake.Generator 实现，可以安全地被多个 goroutine
+// 并发使用
+type FakeGenerator struct {
+	mu        sync.Mutex
+	next      int64   // 递增模式下的下一个 ID
+	script    []int64 // 预设模式下尚未返回的 ID
+	scripted  bool    // 是否为预设模式
+	calls     int     // Generate 和 GenerateBatch 的调用次数
+	requested int     // 成功返回的 ID 总数
+	failOn    int     // 第几次调用返回 failErr，0 表示不失败
+	failErr   error
+}
+
+var _ snowflake.Generator = (*FakeGenerator)(nil)
+
+// NewFakeGenerator 创建一个从 start 开始每次加 1 的 FakeGenerator
+func NewFakeGenerator(start int64) *FakeGenerator {
+	return &FakeGenerator{next: start}
+}
+
+// NewScriptedGenerator 创建一个依次返回 ids 的 FakeGenerator，全部返回后再调用返回
+// ErrScriptExhausted
+func NewScriptedGenerator(ids ...int64) *FakeGenerator {
+	return &FakeGenerator{script: append([]int64(nil), ids...), scripted: true}
+}
+
+// FailOn 让第 n 次调用（从 1 开始，Generate 和 GenerateBatch 合并计数）返回 err，
+// 失败的调用不消耗 ID
+func (f *FakeGenerator) FailOn(n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failOn, f.failErr = n, err
+}
+
+// Generate 返回下一个 ID
+func (f *FakeGenerator) Generate() (int64, error) {
+	ids, err := f.take(1)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// GenerateBatch 返回接下来的 n 个 ID，n 必须在 [1, snowflake.MaxBatchSize] 范围内
+func (f *FakeGenerator) GenerateBatch(n int) ([]int64, error) {
+	if n <= 0 || n > snowflake.MaxBatchSize {
+		return nil, fmt.Errorf("batch size must be between 1 and %d, got %d", snowflake.MaxBatchSize, n)
+	}
+	return f.take(n)
+}
+
+// Calls 返回 Generate 和 GenerateBatch 的调用次数，包括失败的调用
+func (f *FakeGenerator) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// Requested 返回已经成功返回的 ID 总数
+func (f *FakeGenerator) Requested() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requested
+}
+
+// take 记录一次调用并取出 n 个 ID
+func (f *FakeGenerator) take(n int) ([]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.calls == f.failOn {
+		return nil, f.failErr
+	}
+	if f.scripted {
+		if len(f.script) < n {
+			return nil, ErrScriptExhausted
+		}
+		ids := f.script[:n:n]
+		f.script = f.script[n:]
+		f.requested += n
+		return ids, nil
+	}
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = f.next
+		f.next++
+	}
+	f.requested += n
+	return ids, nil
+}
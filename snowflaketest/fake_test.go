@@ -0,0 +1,97 @@
+package snowflaketest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestFakeGenerator(t *testing.T) {
+	f := NewFakeGenerator(100)
+	if id, err := f.Generate(); id != 100 || err != nil {
+		t.Errorf("Generate() = %d, %v, want 100, nil", id, err)
+	}
+	ids, err := f.GenerateBatch(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{101, 102, 103}; !equal(ids, want) {
+		t.Errorf("GenerateBatch(3) = %v, want %v", ids, want)
+	}
+	if _, err := f.GenerateBatch(0); err == nil {
+		t.Error("GenerateBatch(0): want an error")
+	}
+	if f.Calls() != 2 || f.Requested() != 4 {
+		t.Errorf("Calls, Requested = %d, %d, want 2, 4", f.Calls(), f.Requested())
+	}
+}
+
+func TestScriptedGenerator(t *testing.T) {
+	script := []int64{7, 3, 9}
+	f := NewScriptedGenerator(script...)
+	script[0] = 0
+	if id, err := f.Generate(); id != 7 || err != nil {
+		t.Errorf("Generate() = %d, %v, want 7, nil", id, err)
+	}
+	if _, err := f.GenerateBatch(3); !errors.Is(err, ErrScriptExhausted) {
+		t.Errorf("GenerateBatch(3) with 2 IDs left: err = %v, want ErrScriptExhausted", err)
+	}
+	ids, err := f.GenerateBatch(2)
+	if err != nil || !equal(ids, []int64{3, 9}) {
+		t.Errorf("GenerateBatch(2) = %v, %v, want [3 9]", ids, err)
+	}
+	if _, err := f.Generate(); !errors.Is(err, ErrScriptExhausted) {
+		t.Errorf("Generate() after the script: err = %v, want ErrScriptExhausted", err)
+	}
+	if f.Calls() != 4 || f.Requested() != 3 {
+		t.Errorf("Calls, Requested = %d, %d, want 4, 3", f.Calls(), f.Requested())
+	}
+}
+
+func TestFailOn(t *testing.T) {
+	boom := errors.New("boom")
+	f := NewFakeGenerator(1)
+	f.FailOn(2, boom)
+	if _, err := f.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Generate(); !errors.Is(err, boom) {
+		t.Errorf("second Generate: err = %v, want boom", err)
+	}
+	// 失败的调用不消耗 ID
+	if id, err := f.Generate(); id != 2 || err != nil {
+		t.Errorf("third Generate = %d, %v, want 2, nil", id, err)
+	}
+}
+
+func TestFakeGeneratorConcurrent(t *testing.T) {
+	f := NewFakeGenerator(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := f.Generate(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if id, _ := f.Generate(); id != 800 {
+		t.Errorf("Generate after 800 concurrent calls = %d, want 800", id)
+	}
+}
+
+func equal(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
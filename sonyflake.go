@@ -0,0 +1,66 @@
+package snowflake
+
+import "time"
+
+// Sonyflake 兼容模式的参数
+const (
+	// SonyflakeEpoch 是 Sonyflake 默认的基准时间 2014-09-01T00:00:00Z（Unix 毫秒）
+	SonyflakeEpoch = 1409529600000
+	// SonyflakeTimeUnit 是 Sonyflake 的时间单位
+	SonyflakeTimeUnit = 10 * time.Millisecond
+)
+
+// sonyflakeLayoutBits 是 Sonyflake 布局中时间戳之外的位数，时间戳因此只有 39 位
+const sonyflakeLayoutBits = 63 - 39
+
+// sonyflakeLayout 是 Sonyflake 的位布局：16 位机器 ID 和 8 位序列号，没有数据中心 ID
+var sonyflakeLayout = Layout{
+	MachineBits:  16,
+	SequenceBits: 8,
+}
+
+// NewSonyflakeCompat 创建一个生成 Sonyflake 格式 ID 的实例
+//
+// ID 的组成与 Sonyflake 相同：最高位为 0，随后是 39 位以 10 毫秒为单位、相对基准时间
+// 的时间戳，8 位序列号和 16 位机器 ID。注意序列号位于机器 ID 之上，与默认布局的顺序
+// 不同。每 10 毫秒最多生成 256 个 ID，用尽后等待下一个 10 毫秒。基准时间默认为
+// SonyflakeEpoch，可以通过 WithEpoch 修改。machineID 必须在 [0, 65535] 范围内，生成的
+// ID 需要通过 Snowflake.Decompose 或 ParseSonyflake 拆解。
+func NewSonyflakeCompat(machineID int64, opts ...Option) (*Snowflake, error) {
+	return NewSnowflake(machineID, 0, append([]Option{withSonyflake()}, opts...)...)
+}
+
+// NewSonyflake 与 NewSonyflakeCompat 相同，命名与 Sonyflake 库的构造函数对应
+//
+// 时间单位固定为 SonyflakeTimeUnit，基准时间可以通过 WithEpoch 修改，与其他服务共享
+// ID 空间时需要使用与对方相同的基准时间。
+func NewSonyflake(machineID int64, opts ...Option) (*Snowflake, error) {
+	return NewSonyflakeCompat(machineID, opts...)
+}
+
+// withSonyflake 设置 Sonyflake 的位布局、字段顺序、时间单位和基准时间
+func withSonyflake() Option {
+	return func(s *Snowflake) {
+		s.sonyflake = true
+		s.layout = sonyflakeLayout
+		s.unit = SonyflakeTimeUnit
+		s.epoch = SonyflakeEpoch
+	}
+}
+
+// ParseSonyflake 按照 SonyflakeEpoch 拆解 Sonyflake 格式的 ID，不做任何校验
+//
+// 结果中的 MachineID 为 16 位机器 ID，DataCenterID 总是为 0。
+func ParseSonyflake(id int64) Components {
+	return parseSonyflake(uint64(id), SonyflakeEpoch, SonyflakeTimeUnit)
+}
+
+// parseSonyflake 按照 Sonyflake 的字段顺序拆解 ID
+func parseSonyflake(id uint64, epoch int64, unit time.Duration) Components {
+	l := sonyflakeLayout
+	return Components{
+		Timestamp: unitTime(epoch, id>>l.timestampShift(), unit),
+		MachineID: int64(id) & l.maxMachineID(),
+		Sequence:  int64(id>>l.MachineBits) & l.maxSequence(),
+	}
+}
@@ -0,0 +1,132 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// sonyflakeVectors 是 github.com/sony/sonyflake v1.2.0 的 NextID 生成的 ID，elapsed、
+// sequence 和 machine 为同一版本的 sonyflake.Decompose 给出的 time、sequence 和
+// machine-id。生成时使用的 Settings：
+//
+//   - StartTime 为零值（即默认的 2014-09-01T00:00:00Z），MachineID 返回 0x1234 或 1
+//   - StartTime 为 2024-01-01T00:00:00Z，MachineID 返回 0xffff
+var sonyflakeVectors = []struct {
+	start    time.Time // Settings.StartTime，零值表示默认的基准时间
+	id       int64
+	elapsed  int64 // 相对基准时间的 10 毫秒数
+	sequence int64
+	machine  int64
+}{
+	{time.Time{}, 641600399282803252, 38242363887, 0, 0x1234},
+	{time.Time{}, 641600399283130932, 38242363887, 5, 0x1234},
+	{time.Time{}, 641600399299580468, 38242363888, 0, 0x1234},
+	{time.Time{}, 641600406731816961, 38242364330, 255, 1},
+	{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 147448305767481343, 8788603888, 0, 0xffff},
+	{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 147448305767809023, 8788603888, 5, 0xffff},
+}
+
+// sonyflakeVectorTime 返回向量的生成时间所在的 10 毫秒单位的起点
+func sonyflakeVectorTime(start time.Time, elapsed int64) time.Time {
+	if start.IsZero() {
+		start = time.UnixMilli(SonyflakeEpoch)
+	}
+	return start.Add(time.Duration(elapsed) * SonyflakeTimeUnit).UTC()
+}
+
+func TestParseSonyflake(t *testing.T) {
+	for _, v := range sonyflakeVectors {
+		opts := []Option{WithClock(newFakeClock(testStart))}
+		if !v.start.IsZero() {
+			opts = append(opts, WithEpoch(v.start))
+		}
+		s, err := NewSonyflake(v.machine, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Components{Timestamp: sonyflakeVectorTime(v.start, v.elapsed), MachineID: v.machine, Sequence: v.sequence}
+		if c, err := s.Decompose(v.id); err != nil || c != want {
+			t.Errorf("Decompose(%d) = %+v, %v, want %+v", v.id, c, err, want)
+		}
+		if v.start.IsZero() {
+			if c := ParseSonyflake(v.id); c != want {
+				t.Errorf("ParseSonyflake(%d) = %+v, want %+v", v.id, c, want)
+			}
+		}
+	}
+}
+
+func TestNewSonyflakeCompat(t *testing.T) {
+	// 与 sony/sonyflake 在同一个 10 毫秒单位内、以相同的机器 ID 生成的 ID 逐位相同
+	for _, v := range sonyflakeVectors {
+		opts := []Option{WithClock(newFakeClock(sonyflakeVectorTime(v.start, v.elapsed).Add(7 * time.Millisecond)))}
+		if !v.start.IsZero() {
+			opts = append(opts, WithEpoch(v.start))
+		}
+		s, err := NewSonyflakeCompat(v.machine, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var id int64
+		for seq := int64(0); seq <= v.sequence; seq++ {
+			if id, err = s.Generate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if id != v.id {
+			t.Errorf("ID with sequence %d and machine %#x = %d, want %d from sony/sonyflake", v.sequence, v.machine, id, v.id)
+		}
+	}
+}
+
+func TestSonyflakeSequenceWaitsForNextUnit(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSonyflakeCompat(1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last int64
+	for i := 0; i < 257; i++ {
+		if last, err = s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := ParseSonyflake(last)
+	if want := testStart.Add(SonyflakeTimeUnit); !c.Timestamp.Equal(want) || c.Sequence != 0 {
+		t.Errorf("257th ID = %+v, want sequence 0 at %s", c, want)
+	}
+	if got := clock.Now().Sub(testStart); got != SonyflakeTimeUnit {
+		t.Errorf("clock advanced %s, want one 10ms unit", got)
+	}
+}
+
+func TestNewSonyflakeCompatRange(t *testing.T) {
+	if _, err := NewSonyflakeCompat(65535); err != nil {
+		t.Errorf("NewSonyflakeCompat(65535): %v", err)
+	}
+	for _, id := range []int64{-1, 65536} {
+		if _, err := NewSonyflakeCompat(id); !errors.Is(err, ErrMachineIDRange) {
+			t.Errorf("NewSonyflakeCompat(%d): err = %v, want ErrMachineIDRange", id, err)
+		}
+	}
+	if _, err := NewSonyflakeCompat(1, WithTagBits(2)); err == nil {
+		t.Error("NewSonyflakeCompat with tag bits: want an error")
+	}
+}
+
+func TestNewSonyflake(t *testing.T) {
+	// NewSonyflake 与 NewSonyflakeCompat 的输出相同
+	v := sonyflakeVectors[4]
+	clock := newFakeClock(sonyflakeVectorTime(v.start, v.elapsed))
+	s, err := NewSonyflake(v.machine, WithClock(clock), WithEpoch(v.start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := s.Generate(); err != nil || id != v.id {
+		t.Errorf("Generate() = %d, %v, want %d from sony/sonyflake", id, err, v.id)
+	}
+	if _, err := NewSonyflake(1 << 16); !errors.Is(err, ErrMachineIDRange) {
+		t.Errorf("NewSonyflake(65536): err = %v, want ErrMachineIDRange", err)
+	}
+}
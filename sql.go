@@ -0,0 +1,76 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Value 实现 driver.Valuer，以 int64 形式写入数据库
+func (id ID) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// Scan 实现 sql.Scanner，接受 int64、uint64、[]byte 以及十进制字符串
+//
+// 不同驱动对 BIGINT 列返回的类型不同（例如 MySQL 驱动对 UNSIGNED BIGINT 返回
+// uint64），因此都需要支持。NULL 会返回错误，
+// 可为空的列请使用 NullID。
+func (id *ID) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*id = ID(v)
+	case uint64:
+		if v > math.MaxInt64 {
+			return fmt.Errorf("cannot scan %d into snowflake ID: %w", v, ErrOverflow)
+		}
+		*id = ID(v)
+	case []byte:
+		return id.scanString(string(v))
+	case string:
+		return id.scanString(v)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into snowflake ID, use NullID instead")
+	default:
+		return fmt.Errorf("cannot scan %T into snowflake ID", src)
+	}
+	return nil
+}
+
+// scanString 解析十进制字符串形式的 ID
+func (id *ID) scanString(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid snowflake ID %q: %w", s, err)
+	}
+	*id = ID(v)
+	return nil
+}
+
+// NullID 表示一个可能为 NULL 的 ID，用法与 sql.NullInt64 相同
+type NullID struct {
+	ID    ID
+	Valid bool // Valid 为 true 表示 ID 不是 NULL
+}
+
+// Scan 实现 sql.Scanner
+func (n *NullID) Scan(src any) error {
+	if src == nil {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+	if err := n.ID.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value 实现 driver.Valuer
+func (n NullID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.ID), nil
+}
@@ -0,0 +1,52 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIDScan(t *testing.T) {
+	for _, src := range []any{int64(42), []byte("42"), "42"} {
+		var id ID
+		if err := id.Scan(src); err != nil || id != 42 {
+			t.Errorf("Scan(%#v) = %d, %v, want 42", src, id, err)
+		}
+	}
+	for _, src := range []any{nil, "abc", 4.2} {
+		var id ID
+		if err := id.Scan(src); err == nil {
+			t.Errorf("Scan(%#v): err = nil, want an error", src)
+		}
+	}
+	if v, err := ID(42).Value(); err != nil || v != driver.Value(int64(42)) {
+		t.Errorf("Value() = %#v, %v, want int64(42)", v, err)
+	}
+}
+
+func TestNullID(t *testing.T) {
+	n := NullID{ID: 1, Valid: true}
+	if err := n.Scan(nil); err != nil || n.Valid || n.ID != 0 {
+		t.Errorf("Scan(nil) = %+v, %v, want an invalid zero NullID", n, err)
+	}
+	if v, err := n.Value(); err != nil || v != nil {
+		t.Errorf("invalid NullID Value() = %#v, %v, want nil", v, err)
+	}
+	if err := n.Scan(int64(42)); err != nil || !n.Valid || n.ID != 42 {
+		t.Errorf("Scan(42) = %+v, %v, want a valid NullID 42", n, err)
+	}
+	if v, err := n.Value(); err != nil || v != driver.Value(int64(42)) {
+		t.Errorf("Value() = %#v, %v, want int64(42)", v, err)
+	}
+}
+
+func TestIDScanUint64(t *testing.T) {
+	var id ID
+	if err := id.Scan(uint64(42)); err != nil || id != 42 {
+		t.Errorf("Scan(uint64(42)) = %d, %v, want 42", id, err)
+	}
+	if err := id.Scan(uint64(math.MaxInt64) + 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Scan(MaxInt64+1): err = %v, want ErrOverflow", err)
+	}
+}
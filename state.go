@@ -0,0 +1,230 @@
+package snowflake
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultStateInterval 是状态文件默认的写入间隔
+const defaultStateInterval = time.Second
+
+// WithStateFile 将最后一次生成 ID 的时间戳持久化到 path，防止重启后复用已经用过的时间
+//
+// 如果进程在系统时钟被回拨后重启，新的实例会重新生成之前已经用过的时间戳。启用后
+// NewSnowflake 会读取文件中记录的高水位：当前时钟落后于高水位时，RollbackWait 策略
+// 在最大等待时间内等待时钟追上，否则返回包装了 ErrClockMovedBackwards 的错误。
+// 高水位所在的时间单位被视为已经用尽，新实例从下一个时间单位开始生成。
+//
+// Generate 本身不写文件，后台 goroutine 每隔 WithStateInterval 检查一次并在状态变化时
+// 写入，Close 时再写入一次。进程崩溃时最近一个写入间隔内的时间戳可能没有被记录。
+// 文件缺失或损坏时不会导致失败，而是通过 WithStateWarning 设置的回调报告。使用完毕后
+// 必须调用 Close 停止后台 goroutine。
+func WithStateFile(path string) Option {
+	return func(s *Snowflake) {
+		s.statePath = path
+	}
+}
+
+// WithStateInterval 设置状态文件的写入间隔，默认为 1 秒
+func WithStateInterval(d time.Duration) Option {
+	return func(s *Snowflake) {
+		s.stateInterval = d
+	}
+}
+
+// WithStateWarning 设置状态文件读写出现问题时的回调，默认忽略
+//
+// 状态文件缺失、内容损坏或写入失败都会调用该回调，生成器本身继续工作。
+func WithStateWarning(warn func(error)) Option {
+	return func(s *Snowflake) {
+		s.stateWarn = warn
+	}
+}
+
+// stateFile 负责在后台持久化生成器的高水位
+type stateFile struct {
+	path     string
+	interval time.Duration
+	warn     func(error)
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex // 串行化写文件
+	last int64      // 最近一次写入的时间戳字段值
+	seq  int64      // 最近一次写入的序列号
+}
+
+// openState 读取状态文件恢复高水位，并启动后台写入
+func (s *Snowflake) openState() error {
+	st := &stateFile{
+		path:     s.statePath,
+		interval: s.stateInterval,
+		warn:     s.stateWarn,
+		done:     make(chan struct{}),
+		last:     -1,
+	}
+	if st.interval <= 0 {
+		st.interval = defaultStateInterval
+	}
+	if st.warn == nil {
+		st.warn = func(error) {}
+	}
+
+	if watermark, err := readWatermark(st.path); err != nil {
+		st.warn(err)
+	} else if err := s.restoreWatermark(watermark); err != nil {
+		return err
+	}
+
+	s.state = st
+	st.wg.Add(1)
+	go s.flushLoop(st)
+	return nil
+}
+
+// readWatermark 读取状态文件中记录的 Unix 纳秒时间
+func readWatermark(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("read snowflake state file: %w", err)
+	}
+	ns, _, err := parseState(data)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("corrupted snowflake state file %s: %q", path, data)
+	}
+	return time.Unix(0, ns), nil
+}
+
+// appendState 按照状态文件和 SaveState 共用的格式 "<Unix 纳秒> <序列号>\n" 编码状态
+func appendState(dst []byte, ns int64, seq int64) []byte {
+	dst = strconv.AppendInt(dst, ns, 10)
+	dst = append(dst, ' ')
+	dst = strconv.AppendInt(dst, seq, 10)
+	return append(dst, '\n')
+}
+
+// parseState 解析 appendState 编码的状态
+//
+// 也接受旧版本状态文件只有 Unix 纳秒的格式，此时 seq 为 -1，表示序列号未知。
+func parseState(data []byte) (ns int64, seq int64, err error) {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, 0, fmt.Errorf("invalid state %q", data)
+	}
+	if ns, err = strconv.ParseInt(fields[0], 10, 64); err != nil || ns < 0 {
+		return 0, 0, fmt.Errorf("invalid state %q", data)
+	}
+	seq = -1
+	if len(fields) == 2 {
+		if seq, err = strconv.ParseInt(fields[1], 10, 64); err != nil || seq < 0 {
+			return 0, 0, fmt.Errorf("invalid state %q", data)
+		}
+	}
+	return ns, seq, nil
+}
+
+// restoreWatermark 确保之后生成的时间戳晚于高水位
+//
+// 当前时钟落后于高水位时按照回拨策略等待或返回错误，随后把高水位所在的时间单位
+// 标记为已经用尽。
+func (s *Snowflake) restoreWatermark(watermark time.Time) error {
+	mark := (watermark.UnixNano() - s.epoch*int64(time.Millisecond)) / int64(s.unit)
+	if mark < 0 {
+		return nil
+	}
+	if backwards := mark - s.tick(); backwards > 0 {
+		wait := time.Duration(backwards) * s.unit
+		if !s.stateWait && (s.rollbackPolicy != RollbackWait || wait > s.maxRollbackWait) {
+			return fmt.Errorf("%w, persisted state is %s ahead of the current time", ErrClockMovedBackwards, wait)
+		}
+		s.clock.Sleep(wait)
+	}
+	s.restore(mark, s.maxSequence())
+	return nil
+}
+
+// restore 将生成器的最后时间戳和序列号设置为给定值
+func (s *Snowflake) restore(lastTimestamp int64, sequence int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastTimestamp, s.sequence, s.sequenceStart = lastTimestamp, sequence, 0
+	s.packed.Store(uint64(lastTimestamp)<<s.layout.SequenceBits | uint64(sequence))
+}
+
+// flushLoop 定期把高水位写入状态文件，直到 st.done 被关闭
+func (s *Snowflake) flushLoop(st *stateFile) {
+	defer st.wg.Done()
+	t := time.NewTicker(st.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.flushState(st)
+		case <-st.done:
+			return
+		}
+	}
+}
+
+// flushState 在高水位变化时写入状态文件
+//
+// 先写临时文件再重命名，避免进程中途退出留下不完整的文件。
+func (s *Snowflake) flushState(st *stateFile) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	last, seq := s.snapshot()
+	if last == 0 || (last == st.last && seq == st.seq) {
+		// 从未生成也没有恢复过高水位时不写入，避免用基准时间覆盖已有的文件
+		return
+	}
+	data := appendState(nil, s.timeOf(last).UnixNano(), seq)
+
+	tmp, err := os.CreateTemp(filepath.Dir(st.path), filepath.Base(st.path)+".tmp*")
+	if err != nil {
+		st.warn(fmt.Errorf("write snowflake state file: %w", err))
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), st.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		st.warn(fmt.Errorf("write snowflake state file: %w", err))
+		return
+	}
+	st.last, st.seq = last, seq
+}
+
+// Close 关闭生成器并释放持有的资源
+//
+// 关闭后所有生成方法都返回 ErrClosed。启用 WithStateFile 时停止后台写入并把最终的
+// 高水位写入状态文件；通过 NewSnowflakeWithAllocator 创建时归还租用的机器 ID，并返回
+// 归还失败的错误。可以安全地多次调用，只有第一次调用会执行清理，之后的调用返回 nil。
+func (s *Snowflake) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.closed.Store(true)
+		if st := s.state; st != nil {
+			close(st.done)
+			st.wg.Wait()
+			s.flushState(st)
+		}
+		if s.allocator != nil {
+			if rerr := s.allocator.Release(); rerr != nil {
+				err = fmt.Errorf("release machine ID: %w", rerr)
+			}
+		}
+	})
+	return err
+}
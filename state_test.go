@@ -0,0 +1,242 @@
+package snowflake
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSaveStateReadByStateFile(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := s.SaveState(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 时钟回拨到保存之前，新实例必须等待时钟追上并跳过保存时的时间单位
+	clock.Advance(-50 * time.Millisecond)
+	var warnings []error
+	r, err := NewSnowflakeWithStateFile(path, 1, 1, WithClock(clock), WithStateWarning(func(err error) { warnings = append(warnings, err) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := r.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	if id <= last {
+		t.Errorf("ID after restore = %d, want greater than %d", id, last)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.LoadState(bytes.NewReader(data)); err != nil {
+		t.Fatalf("LoadState of the state file: %v", err)
+	}
+	if next, _ := loaded.Generate(); next <= id {
+		t.Errorf("ID after LoadState = %d, want greater than %d", next, id)
+	}
+}
+
+func TestStateFileNotOverwrittenWithEpoch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(path, []byte("garbage\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var warned bool
+	s, err := NewSnowflake(1, 1, WithStateFile(path), WithStateWarning(func(error) { warned = true }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !warned {
+		t.Error("corrupted state file was not reported")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "garbage\n" {
+		t.Errorf("state file overwritten with %q without generating any ID", data)
+	}
+}
+
+func TestStateFileLegacyFormat(t *testing.T) {
+	clock := newFakeClock(testStart)
+	path := filepath.Join(t.TempDir(), "state")
+	mark := testStart.Add(10 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(strconv.FormatInt(mark.UnixNano(), 10)+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSnowflakeWithStateFile(path, 1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Parse(id).Timestamp; !got.After(mark) {
+		t.Errorf("timestamp after restoring legacy state = %s, want after %s", got, mark)
+	}
+}
+
+func TestLoadStateInvalid(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range []string{"", "abc", "-1 0", "1 -1", "1 2 3", "1 4096"} {
+		if err := s.LoadState(bytes.NewReader([]byte(in))); err == nil {
+			t.Errorf("LoadState(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestStateFileWatermark(t *testing.T) {
+	clock := newFakeClock(testStart)
+	path := filepath.Join(t.TempDir(), "state")
+	var warned bool
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithStateFile(path), WithStateWarning(func(error) { warned = true }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !warned {
+		t.Error("missing state file was not reported")
+	}
+	last, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重启前时钟回拨：默认策略拒绝创建，RollbackWait 等待时钟越过高水位
+	clock.Advance(-3 * time.Millisecond)
+	if _, err := NewSnowflake(1, 1, WithClock(clock), WithStateFile(path)); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Fatalf("clock behind the watermark: err = %v, want ErrClockMovedBackwards", err)
+	}
+	r, err := NewSnowflake(1, 1, WithClock(clock), WithStateFile(path), WithRollbackPolicy(RollbackWait))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	id, err := r.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Parse(id).Timestamp.After(Parse(last).Timestamp) {
+		t.Errorf("ID after restart at %s, want after the watermark %s", Parse(id).Timestamp, Parse(last).Timestamp)
+	}
+}
+
+func TestClosedGeneratorReturnsErrClosed(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		opts := []Option{WithClock(newFakeClock(testStart)), WithTagBits(1)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		errOf := func(_ any, err error) error { return err }
+		for name, err := range map[string]error{
+			"Generate":              errOf(s.Generate()),
+			"GenerateContext":       errOf(s.GenerateContext(context.Background())),
+			"GenerateN":             errOf(s.GenerateN(2)),
+			"GenerateBatch":         errOf(s.GenerateBatch(2)),
+			"Fill":                  errOf(s.Fill(make([]int64, 2))),
+			"NextWithin":            errOf(s.NextWithin(time.Second)),
+			"GenerateTagged":        errOf(s.GenerateTagged(1)),
+			"GenerateForShard":      errOf(s.GenerateForShard(1)),
+			"GenerateAt":            errOf(s.GenerateAt(testStart.Add(-time.Hour))),
+			"GenerateID":            errOf(s.GenerateID()),
+			"GenerateUint64":        errOf(s.GenerateUint64()),
+			"GenerateString":        errOf(s.GenerateString()),
+			"GenerateDescendingKey": errOf(s.GenerateDescendingKey()),
+			"GenerateUUID":          errOf(s.GenerateUUID()),
+		} {
+			if !errors.Is(err, ErrClosed) {
+				t.Errorf("lock-free %t: %s after Close: err = %v, want ErrClosed", lockFree, name, err)
+			}
+		}
+		if _, _, err := s.Reserve(2); !errors.Is(err, ErrClosed) {
+			t.Errorf("lock-free %t: Reserve after Close: err = %v, want ErrClosed", lockFree, err)
+		}
+		if _, ok := s.TryGenerate(); ok {
+			t.Errorf("lock-free %t: TryGenerate after Close succeeded", lockFree)
+		}
+		if _, ok := <-s.Stream(context.Background()); ok {
+			t.Errorf("lock-free %t: Stream after Close delivered an ID", lockFree)
+		}
+	}
+}
+
+func TestCloseIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithStateFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close %d: %v", i, err)
+		}
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Second)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Errorf("a repeated Close rewrote the state file: %q -> %q", before, after)
+	}
+}
@@ -0,0 +1,59 @@
+package snowflake
+
+import "time"
+
+// Stats 是生成器运行状态的快照
+type Stats struct {
+	TotalGenerated         uint64    // 已经生成的 ID 总数
+	SequenceExhaustedCount uint64    // 序列号用尽后等待下一毫秒的次数
+	ClockRollbackCount     uint64    // 检测到时钟回拨的次数
+	LastGeneratedAt        time.Time // 最后一次生成 ID 的毫秒时间（UTC），尚未生成时为基准时间
+	CurrentSequence        int64     // 当前毫秒最后使用的序列号
+	MaxSequence            int64     // 序列号上限
+	Now                    time.Time // 读取快照时生成器时钟所在的时间单位的起点（UTC），与 LastGeneratedAt 使用同一时钟和精度
+}
+
+// Stats 返回生成器运行状态的快照，返回值是副本，不会随之后的生成而变化
+//
+// 计数器通过原子操作维护，读取时不会阻塞 Generate。CurrentSequence 接近 MaxSequence
+// 说明当前节点已经逼近每毫秒的生成上限，可以据此做限流或扩容。LastGeneratedAt 不早于
+// Now 时说明 CurrentSequence 属于当前时间单位。
+func (s *Snowflake) Stats() Stats {
+	last, seq := s.snapshot()
+	return Stats{
+		TotalGenerated:         s.generated.Load(),
+		SequenceExhaustedCount: s.exhausted.Load(),
+		ClockRollbackCount:     s.rollbacks.Load(),
+		LastGeneratedAt:        s.timeOf(last),
+		CurrentSequence:        seq,
+		MaxSequence:            s.maxSequence(),
+		Now:                    s.timeOf(s.tick()),
+	}
+}
+
+// countGenerated 记录成功生成了一个 ID
+func (s *Snowflake) countGenerated() {
+	s.generated.Add(1)
+	s.observer.IncGenerated()
+}
+
+// countClockWait 记录序列号用尽后需要等待下一时间单位
+func (s *Snowflake) countClockWait() {
+	s.exhausted.Add(1)
+	s.observer.IncClockWait()
+}
+
+// countClockBackwards 记录检测到一次时钟回拨
+func (s *Snowflake) countClockBackwards() {
+	s.rollbacks.Add(1)
+	s.observer.IncClockBackwards()
+}
+
+// TheoreticalMaxPerSecond 返回实例配置下每秒最多能生成的 ID 数量
+//
+// 等于每个时间单位的序列号数量乘以每秒的时间单位数，默认配置下为 4096 * 1000。该值
+// 只取决于序列号位宽和时间单位，是单个实例的理论上限，实际吞吐还受锁竞争和等待
+// 策略影响。时间单位大于一秒时向下取整。
+func (s *Snowflake) TheoreticalMaxPerSecond() int64 {
+	return (s.maxSequence() + 1) * int64(time.Second) / int64(s.unit)
+}
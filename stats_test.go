@@ -0,0 +1,111 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStatsCurrentTick(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := s.Stats(); !st.LastGeneratedAt.Before(st.Now) {
+		t.Errorf("Stats before generating: LastGeneratedAt %s, want before Now %s", st.LastGeneratedAt, st.Now)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st := s.Stats()
+	if st.CurrentSequence != 9 || st.MaxSequence != MaxSequence || !st.LastGeneratedAt.Equal(testStart) || !st.Now.Equal(testStart) {
+		t.Errorf("Stats = %+v, want sequence 9 of %d generated at %s", st, MaxSequence, testStart)
+	}
+	if st.TotalGenerated != 10 {
+		t.Errorf("TotalGenerated = %d, want 10", st.TotalGenerated)
+	}
+
+	clock.Advance(time.Millisecond)
+	if st := s.Stats(); !st.LastGeneratedAt.Before(st.Now) {
+		t.Errorf("Stats in the next millisecond: LastGeneratedAt %s, want before Now %s", st.LastGeneratedAt, st.Now)
+	}
+}
+
+func TestStatsCounters(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithSequenceBits(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GenerateN(9); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(-time.Millisecond)
+	s.Generate()
+	st := s.Stats()
+	if st.TotalGenerated != 9 || st.SequenceExhaustedCount != 2 || st.ClockRollbackCount != 1 {
+		t.Errorf("Stats = %+v, want 9 generated, 2 exhausted and 1 rollback", st)
+	}
+}
+
+func TestStatsConcurrent(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithLockFree()}} {
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		const goroutines, perGoroutine = 8, 2000
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < perGoroutine; i++ {
+					if _, err := s.Generate(); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+		}
+		// 生成期间读取快照不会阻塞或产生数据竞争
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 1000; i++ {
+				if st := s.Stats(); st.CurrentSequence > st.MaxSequence {
+					t.Errorf("CurrentSequence %d exceeds MaxSequence %d", st.CurrentSequence, st.MaxSequence)
+				}
+			}
+		}()
+		wg.Wait()
+		<-done
+		if got := s.Stats().TotalGenerated; got != goroutines*perGoroutine {
+			t.Errorf("TotalGenerated = %d, want %d", got, goroutines*perGoroutine)
+		}
+	}
+}
+
+func TestTheoreticalMaxPerSecond(t *testing.T) {
+	for _, tc := range []struct {
+		opts []Option
+		want int64
+	}{
+		{nil, 4096 * 1000},
+		{[]Option{WithSequenceBits(8)}, 256 * 1000},
+		{[]Option{WithTimeUnit(10 * time.Millisecond)}, 4096 * 100},
+		{[]Option{WithTimeUnit(time.Microsecond), WithEpoch(testStart.Add(-time.Hour))}, 4096 * 1000000},
+		{[]Option{WithTimeUnit(3 * time.Second), WithSequenceBits(2)}, 1},
+	} {
+		s, err := NewSnowflake(1, 1, append([]Option{WithClock(newFakeClock(testStart))}, tc.opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.TheoreticalMaxPerSecond(); got != tc.want {
+			t.Errorf("TheoreticalMaxPerSecond() = %d, want %d", got, tc.want)
+		}
+	}
+}
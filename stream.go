@@ -0,0 +1,41 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+)
+
+// Stream 返回一个持续输出新 ID 的通道，直到 ctx 被取消或生成器被关闭
+//
+// 通道没有缓冲，消费者读取的速度就是生成的速度，ID 在被读取之前不会提前生成。ctx 被
+// 取消或 Close 后后台 goroutine 退出并关闭通道，因此可以直接使用
+//
+//	for id := range sf.Stream(ctx) { ... }
+//
+// 时钟回拨等生成错误会被静默丢弃，并在等待一个时间单位后重试；需要感知错误的调用方
+// 应当直接调用 GenerateContext。
+func (s *Snowflake) Stream(ctx context.Context) <-chan int64 {
+	ch := make(chan int64)
+	go func() {
+		defer close(ch)
+		for {
+			id, err := s.GenerateContext(ctx)
+			if err != nil {
+				if ctx.Err() != nil || errors.Is(err, ErrClosed) {
+					return
+				}
+				// 避免时钟回拨期间空转
+				if s.sleepContext(ctx, s.unit) != nil {
+					return
+				}
+				continue
+			}
+			select {
+			case ch <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
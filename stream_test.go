@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// drain 等待 ch 被关闭，超时视为测试失败
+func drain(t *testing.T, ch <-chan int64) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("stream was not closed")
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := s.Stream(ctx)
+	var last int64
+	for i := 0; i < 100; i++ {
+		id := <-ch
+		if id <= last {
+			t.Fatalf("ID %d = %d, not greater than previous %d", i, id, last)
+		}
+		last = id
+	}
+	cancel()
+	drain(t, ch)
+}
+
+func TestStreamStopsOnClose(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := s.Stream(context.Background())
+	<-ch
+	s.Close()
+	drain(t, ch)
+}
+
+func TestStreamRetriesAfterRollback(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 回拨 3ms，Stream 丢弃错误并通过时钟等待，直到时钟追上
+	clock.Advance(-3 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	select {
+	case id := <-s.Stream(ctx):
+		if id <= first {
+			t.Errorf("ID after rollback = %d, want greater than %d", id, first)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stream did not recover from the rollback")
+	}
+}
@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateTagged(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		opts := []Option{WithClock(newFakeClock(testStart)), WithTagBits(3)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(5, 2, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[int64]bool)
+		for i := int64(0); i < 16; i++ {
+			tag := i % 8
+			id, err := s.GenerateTagged(tag)
+			if err != nil {
+				t.Fatalf("GenerateTagged(%d): %v", tag, err)
+			}
+			if seen[id] {
+				t.Fatalf("duplicate ID %d", id)
+			}
+			seen[id] = true
+			c := s.Parse(id)
+			if c.Tag != tag || c.Sequence != i || c.MachineID != 5 || c.DataCenterID != 2 {
+				t.Errorf("lockFree=%v: Parse(%d) = %+v, want tag %d sequence %d", lockFree, id, c, tag, i)
+			}
+		}
+	}
+}
+
+func TestGenerateTaggedRange(t *testing.T) {
+	s, err := NewSnowflake(1, 1, WithClock(newFakeClock(testStart)), WithTagBits(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []int64{-1, 4} {
+		_, err := s.GenerateTagged(tag)
+		if !errors.Is(err, ErrTagRange) {
+			t.Errorf("GenerateTagged(%d): err = %v, want ErrTagRange", tag, err)
+		}
+		var re *RangeError
+		if !errors.As(err, &re) || re.Value != tag || re.Max != 3 {
+			t.Errorf("GenerateTagged(%d): err = %#v, want a RangeError with max 3", tag, err)
+		}
+	}
+}
+
+func TestWithTagBitsValidation(t *testing.T) {
+	for _, k := range []int{-1, 12} {
+		if _, err := NewSnowflake(1, 1, WithTagBits(k)); err == nil {
+			t.Errorf("WithTagBits(%d): want an error", k)
+		}
+	}
+	if _, err := NewSnowflake(1, 1, WithTagBits(11)); err != nil {
+		t.Errorf("WithTagBits(11): %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MarshalText 实现 encoding.TextMarshaler，输出十进制字符串
+//
+// 实现该接口后 ID 可以作为 encoding/json 的 map 键，也能被 YAML、TOML 等基于文本的
+// 编码库正确处理。
+func (id ID) MarshalText() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(id), 10), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler，只接受不带符号的十进制字符串
+//
+// 空输入、带 '+' 或 '-' 符号的输入以及超出 int64 范围的值都返回错误。
+func (id *ID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return fmt.Errorf("invalid snowflake ID: empty string")
+	}
+	for _, c := range text {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("invalid snowflake ID %q: %w %q", text, ErrInvalidCharacter, c)
+		}
+	}
+	v, err := strconv.ParseInt(string(text), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid snowflake ID %q: %w", text, ErrOverflow)
+	}
+	*id = ID(v)
+	return nil
+}
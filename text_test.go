@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestIDText(t *testing.T) {
+	text, err := ID(1234567890123456789).MarshalText()
+	if err != nil || string(text) != "1234567890123456789" {
+		t.Errorf("MarshalText() = %q, %v, want %q", text, err, "1234567890123456789")
+	}
+	var id ID
+	if err := id.UnmarshalText(text); err != nil || id != 1234567890123456789 {
+		t.Errorf("UnmarshalText(%q) = %d, %v", text, id, err)
+	}
+	for _, tc := range []struct {
+		in   string
+		want error
+	}{
+		{"+1", ErrInvalidCharacter},
+		{"-1", ErrInvalidCharacter},
+		{"12a", ErrInvalidCharacter},
+		{"9223372036854775808", ErrOverflow},
+	} {
+		if err := id.UnmarshalText([]byte(tc.in)); !errors.Is(err, tc.want) {
+			t.Errorf("UnmarshalText(%q): err = %v, want %v", tc.in, err, tc.want)
+		}
+	}
+	if err := id.UnmarshalText(nil); err == nil {
+		t.Error("UnmarshalText(empty): err = nil, want an error")
+	}
+}
+
+func TestIDAsJSONMapKey(t *testing.T) {
+	data, err := json.Marshal(map[ID]string{42: "a"})
+	if err != nil || string(data) != `{"42":"a"}` {
+		t.Fatalf("Marshal map = %s, %v, want {\"42\":\"a\"}", data, err)
+	}
+	var m map[ID]string
+	if err := json.Unmarshal(data, &m); err != nil || m[42] != "a" {
+		t.Errorf("Unmarshal map = %v, %v, want key 42", m, err)
+	}
+}
+
+func TestIDYAML(t *testing.T) {
+	type record struct {
+		ID   ID            `yaml:"id"`
+		IDs  []ID          `yaml:"ids"`
+		Keys map[ID]string `yaml:"keys"`
+	}
+	in := record{ID: 1234567890123456789, IDs: []ID{0, 1<<63 - 1}, Keys: map[ID]string{42: "a"}}
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out record
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal(%q): %v", data, err)
+	}
+	if out.ID != in.ID || len(out.IDs) != 2 || out.IDs[0] != 0 || out.IDs[1] != 1<<63-1 || out.Keys[42] != "a" {
+		t.Errorf("YAML round trip of %q = %+v, want %+v", data, out, in)
+	}
+	if err := yaml.Unmarshal([]byte("id: 12a\n"), &out); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf("Unmarshal(id: 12a): err = %v, want ErrInvalidCharacter", err)
+	}
+}
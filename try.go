@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TryGenerate 尝试生成 ID，从不阻塞
+//
+// 如果当前毫秒的序列号已经用尽，立即返回 ok=false 而不是等待下一毫秒，调用方可以
+// 据此退避或自行排队，实现显式的背压。时钟回拨等错误同样返回 ok=false，即使配置了
+// RollbackWait 也不会等待时钟恢复。成功时的行为、锁语义和唯一性保证与 Generate
+// 完全相同；失败时不会修改生成器的状态，也不会把 lastTimestamp 推进到当前时钟之后。
+func (s *Snowflake) TryGenerate() (id int64, ok bool) {
+	var err error
+	if s.lockFree {
+		id, err = s.nextAtomic(context.Background(), false)
+	} else {
+		s.mu.Lock()
+		id, err = s.nextLocked(context.Background(), false)
+		s.mu.Unlock()
+	}
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// NextWithin 生成 ID，当前毫秒的序列号用尽时最多等待 d
+//
+// 如果在 d 内时钟无法前进到下一毫秒，立即返回包装了 ErrSequenceExhausted 的错误，
+// 而不会先休眠再失败，从而限制单次调用在突发流量下的最长阻塞时间。等待和计时都通过
+// 实例的时间源完成，注入假时钟时可以不真正休眠地测试超时路径。与 TryGenerate 一样，
+// 时钟回拨时不会等待。
+func (s *Snowflake) NextWithin(d time.Duration) (int64, error) {
+	deadline := s.clock.Now().Add(d)
+	for {
+		var id int64
+		var err error
+		if s.lockFree {
+			id, err = s.nextAtomic(context.Background(), false)
+		} else {
+			s.mu.Lock()
+			id, err = s.nextLocked(context.Background(), false)
+			s.mu.Unlock()
+		}
+		if !errors.Is(err, ErrSequenceExhausted) {
+			return id, err
+		}
+		wait := s.untilNextTick()
+		if s.clock.Now().Add(wait).After(deadline) {
+			return 0, fmt.Errorf("%w: no ID available within %s", ErrSequenceExhausted, d)
+		}
+		s.clock.Sleep(wait)
+	}
+}
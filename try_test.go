@@ -0,0 +1,135 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTryGenerate(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		clock := newFakeClock(testStart)
+		opts := []Option{WithClock(clock), WithSequenceBits(2)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 4; i++ {
+			if _, ok := s.TryGenerate(); !ok {
+				t.Fatalf("lock-free %t: TryGenerate %d failed before the sequence was exhausted", lockFree, i)
+			}
+		}
+		if id, ok := s.TryGenerate(); ok {
+			t.Fatalf("lock-free %t: TryGenerate with an exhausted sequence = %d, want ok=false", lockFree, id)
+		}
+		if !clock.Now().Equal(testStart) {
+			t.Errorf("lock-free %t: TryGenerate waited, clock moved to %s", lockFree, clock.Now())
+		}
+		clock.Advance(time.Millisecond)
+		if id, ok := s.TryGenerate(); !ok || s.Parse(id).Sequence != 0 {
+			t.Errorf("lock-free %t: TryGenerate in the next millisecond = %d, %t, want sequence 0", lockFree, id, ok)
+		}
+	}
+}
+
+func TestTryGenerateRollback(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithRollbackPolicy(RollbackWait))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.TryGenerate(); !ok {
+		t.Fatal("first TryGenerate failed")
+	}
+	clock.Advance(-time.Millisecond)
+	if _, ok := s.TryGenerate(); ok {
+		t.Error("TryGenerate after rollback succeeded, want ok=false without waiting")
+	}
+	if !clock.Now().Equal(testStart.Add(-time.Millisecond)) {
+		t.Errorf("TryGenerate waited for the rollback, clock moved to %s", clock.Now())
+	}
+}
+
+func TestTryGenerateFailureKeepsState(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithSequenceBits(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, ok := s.TryGenerate(); !ok {
+			t.Fatal("TryGenerate failed before the sequence was exhausted")
+		}
+	}
+	before := s.Stats()
+	for i := 0; i < 10; i++ {
+		if _, ok := s.TryGenerate(); ok {
+			t.Fatal("TryGenerate succeeded with an exhausted sequence")
+		}
+	}
+	after := s.Stats()
+	if after.TotalGenerated != before.TotalGenerated || after.CurrentSequence != before.CurrentSequence ||
+		!after.LastGeneratedAt.Equal(before.LastGeneratedAt) {
+		t.Errorf("failed TryGenerate changed the state from %+v to %+v", before, after)
+	}
+}
+
+func TestNextWithin(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		start := testStart.Add(300 * time.Microsecond)
+		clock := newFakeClock(start)
+		opts := []Option{WithClock(clock), WithSequenceBits(2)}
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		s, err := NewSnowflake(1, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 4; i++ {
+			if _, err := s.NextWithin(0); err != nil {
+				t.Fatalf("lock-free %t: NextWithin %d: %v", lockFree, i, err)
+			}
+		}
+
+		// 距离下一毫秒还有 700µs，超出期限时立即失败而不休眠
+		if _, err := s.NextWithin(500 * time.Microsecond); !errors.Is(err, ErrSequenceExhausted) {
+			t.Errorf("lock-free %t: NextWithin(500µs): err = %v, want ErrSequenceExhausted", lockFree, err)
+		}
+		if !clock.Now().Equal(start) {
+			t.Errorf("lock-free %t: NextWithin slept before failing, clock moved to %s", lockFree, clock.Now())
+		}
+
+		id, err := s.NextWithin(700 * time.Microsecond)
+		if err != nil {
+			t.Fatalf("lock-free %t: NextWithin(700µs): %v", lockFree, err)
+		}
+		if c := s.Parse(id); !c.Timestamp.Equal(testStart.Add(time.Millisecond)) || c.Sequence != 0 {
+			t.Errorf("lock-free %t: NextWithin(700µs) = %+v, want sequence 0 in the next millisecond", lockFree, c)
+		}
+		if want := testStart.Add(time.Millisecond); !clock.Now().Equal(want) {
+			t.Errorf("lock-free %t: clock = %s, want %s", lockFree, clock.Now(), want)
+		}
+	}
+}
+
+func TestNextWithinRollback(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithRollbackPolicy(RollbackWait))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.NextWithin(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(-time.Millisecond)
+	if _, err := s.NextWithin(time.Second); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("NextWithin after rollback: err = %v, want ErrClockMovedBackwards", err)
+	}
+	if want := testStart.Add(-time.Millisecond); !clock.Now().Equal(want) {
+		t.Errorf("NextWithin waited for the rollback, clock moved to %s", clock.Now())
+	}
+}
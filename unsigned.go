@@ -0,0 +1,70 @@
+package snowflake
+
+import "time"
+
+// WithUnsigned 把 int64 中闲置的符号位也分配给时间戳，使时间戳多出 1 位
+//
+// 默认配置下时间戳可用的年限因此翻倍，适合以无符号整数列存储 ID 的系统。时间戳的
+// 最高位被使用后，ID 的 int64 视图会变成负数，因此该模式下应当使用 GenerateUint64
+// 生成、使用 ParseUint64 拆解。Generate 等返回 int64 的方法依然可用，返回的是同一组
+// 二进制位的 int64 视图，可以通过 ToUint64 转换。
+func WithUnsigned() Option {
+	return func(s *Snowflake) {
+		s.unsigned = true
+		s.idBits = 64
+	}
+}
+
+// GenerateUint64 生成一个以 uint64 表示的唯一 ID
+//
+// 未启用 WithUnsigned 时，结果与 Generate 的返回值数值相同。
+func (s *Snowflake) GenerateUint64() (uint64, error) {
+	id, err := s.Generate()
+	if err != nil {
+		return 0, err
+	}
+	return ToUint64(id), nil
+}
+
+// ToUint64 返回 ID 的 uint64 视图，二进制位保持不变
+func ToUint64(id int64) uint64 {
+	return uint64(id)
+}
+
+// FromUint64 返回 ID 的 int64 视图，二进制位保持不变，最高位为 1 时结果为负数
+func FromUint64(id uint64) int64 {
+	return int64(id)
+}
+
+// ParseUint64 按照默认的位布局和 Epoch 拆解 uint64 ID，时间戳可以占用最高位
+func ParseUint64(id uint64) Components {
+	return defaultLayout.parseUint64(id, Epoch, time.Millisecond)
+}
+
+// ParseUint64 按照实例的基准时间和位布局拆解 uint64 ID，不做任何校验
+func (s *Snowflake) ParseUint64(id uint64) Components {
+	if s.sonyflake {
+		return parseSonyflake(id, s.epoch, s.unit)
+	}
+	return s.splitTag(s.layout.parseUint64(id, s.epoch, s.unit))
+}
+
+// timestampBits 返回时间戳字段的位宽
+func (s *Snowflake) timestampBits() int {
+	return s.idBits - s.layout.timestampShift()
+}
+
+// maxTimestamp 返回实例的时间戳字段能表示的最大值
+func (s *Snowflake) maxTimestamp() int64 {
+	return -1 ^ (-1 << s.timestampBits())
+}
+
+// parseUint64 与 parse 相同，但使用无符号移位，避免最高位为 1 时符号扩展
+func (l Layout) parseUint64(id uint64, epoch int64, unit time.Duration) Components {
+	return Components{
+		Timestamp:    unitTime(epoch, id>>l.timestampShift(), unit),
+		DataCenterID: int64(id>>l.dataCenterShift()) & l.maxDataCenterID(),
+		MachineID:    int64(id>>l.machineShift()) & l.maxMachineID(),
+		Sequence:     int64(id) & l.maxSequence(),
+	}
+}
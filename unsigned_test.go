@@ -0,0 +1,133 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUnsigned(t *testing.T) {
+	// 约 74 年前的基准时间超出了 41 位时间戳的范围，需要借用符号位
+	epoch := time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := newFakeClock(testStart)
+	if _, err := NewSnowflake(1, 1, WithClock(clock), WithEpoch(epoch)); err == nil {
+		t.Fatal("signed layout with a 74-year-old epoch: err = nil, want an error")
+	}
+	s, err := NewSnowflake(7, 3, WithClock(clock), WithEpoch(epoch), WithUnsigned())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.GenerateUint64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u>>63 != 1 {
+		t.Errorf("ID %d does not use the top bit", u)
+	}
+	want := Components{Timestamp: testStart, DataCenterID: 3, MachineID: 7}
+	if got := s.ParseUint64(u); got != want {
+		t.Errorf("ParseUint64(%d) = %+v, want %+v", u, got, want)
+	}
+	if id := FromUint64(u); id >= 0 || ToUint64(id) != u {
+		t.Errorf("FromUint64(%d) = %d, want a negative int64 view that converts back", u, id)
+	}
+}
+
+func TestParseUint64Default(t *testing.T) {
+	s, err := NewSnowflake(7, 3, WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.GenerateUint64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ParseUint64(u), Parse(int64(u)); got != want {
+		t.Errorf("ParseUint64(%d) = %+v, want the same as Parse %+v", u, got, want)
+	}
+}
+
+func TestUnsignedStringEncodings(t *testing.T) {
+	// 微秒时间单位下 41 位时间戳只能覆盖约 25 天，30 天前的基准时间让时间戳占用最高位
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(1, 1, WithClock(clock), WithEpoch(testStart.Add(-30*24*time.Hour)),
+		WithTimeUnit(time.Microsecond), WithUnsigned())
+	if err != nil {
+		t.Fatal(err)
+	}
+	type encoding struct {
+		name     string
+		generate func() (string, error)
+		decode   func(string) (uint64, error)
+	}
+	for _, e := range []encoding{
+		{"GenerateString", s.GenerateString, FromBase62Uint64},
+		{"GenerateBase62String", s.GenerateBase62String, FromBase62Uint64},
+		{"GenerateBase32", s.GenerateBase32, FromCrockfordUint64},
+		{"GenerateDescendingKey", s.GenerateDescendingKey, FromDescendingKeyUint64},
+	} {
+		var prev string
+		for i := 0; i < 3; i++ {
+			str, err := e.generate()
+			if err != nil {
+				t.Fatalf("%s: %v", e.name, err)
+			}
+			id, err := e.decode(str)
+			if err != nil {
+				t.Fatalf("%s: decode %q: %v", e.name, str, err)
+			}
+			if id>>63 != 1 {
+				t.Fatalf("%s: ID %d does not use the top bit", e.name, id)
+			}
+			if got := ParseUint64(id); got.MachineID != 1 || got.DataCenterID != 1 {
+				t.Errorf("%s: decoded ID %d has node (%d, %d), want (1, 1)", e.name, id, got.DataCenterID, got.MachineID)
+			}
+			descending := e.name == "GenerateDescendingKey"
+			if i > 0 && (str > prev) == descending {
+				t.Errorf("%s: %q after %q is out of order", e.name, str, prev)
+			}
+			prev = str
+			clock.Advance(time.Microsecond)
+		}
+	}
+}
+
+func TestUint64EncodingVectors(t *testing.T) {
+	const maxUint64 = 1<<64 - 1
+	for _, tc := range []struct {
+		name   string
+		id     uint64
+		enc    string
+		encode func(uint64) string
+		decode func(string) (uint64, error)
+	}{
+		{"base62 max", maxUint64, "LygHa16AHYF", ToBase62Uint64, FromBase62Uint64},
+		{"base62 int64 max", 1<<63 - 1, "AzL8n0Y58m7", ToBase62Uint64, FromBase62Uint64},
+		{"base32 max", maxUint64, "FZZZZZZZZZZZZ", ToCrockfordUint64, FromCrockfordUint64},
+		{"base32 top bit", 1 << 63, "8000000000000", ToCrockfordUint64, FromCrockfordUint64},
+		{"descending max", maxUint64, "00000000000", ToDescendingKeyUint64, FromDescendingKeyUint64},
+		{"descending zero", 0, "LygHa16AHYF", ToDescendingKeyUint64, FromDescendingKeyUint64},
+	} {
+		if got := tc.encode(tc.id); got != tc.enc {
+			t.Errorf("%s: encode(%d) = %q, want %q", tc.name, tc.id, got, tc.enc)
+		}
+		if got, err := tc.decode(tc.enc); err != nil || got != tc.id {
+			t.Errorf("%s: decode(%q) = %d, %v, want %d", tc.name, tc.enc, got, err, tc.id)
+		}
+	}
+	for _, e := range []struct {
+		name   string
+		in     string
+		decode func(string) (uint64, error)
+	}{
+		{"base62", "LygHa16AHYG", FromBase62Uint64},
+		{"base32", "G000000000000", FromCrockfordUint64},
+	} {
+		if _, err := e.decode(e.in); !errors.Is(err, ErrOverflow) {
+			t.Errorf("%s: decode(%q): err = %v, want ErrOverflow", e.name, e.in, err)
+		}
+	}
+	if _, err := FromBase62("LygHa16AHYF"); !errors.Is(err, ErrOverflow) {
+		t.Errorf("FromBase62 of a uint64 beyond int64: err = %v, want ErrOverflow", err)
+	}
+}
@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID 是一个 128 位的 UUID 值
+type UUID [16]byte
+
+// GenerateUUID 生成一个嵌入了 Snowflake ID 的 UUID
+//
+// 布局为标准的 UUIDv4 形式（版本号 4，RFC 4122 变体），无损地容纳 64 位 ID：
+//
+//	字节 0-5   ID 的第 63-16 位
+//	字节 6     版本号 4 | ID 的第 15-12 位
+//	字节 7     ID 的第 11-4 位
+//	字节 8     变体 10 | 2 位随机数 | ID 的第 3-0 位
+//	字节 9-15  随机数
+//
+// ID 的高位位于 UUID 的高位，因此 UUID 按字节比较时大致保持生成顺序。
+func (s *Snowflake) GenerateUUID() (UUID, error) {
+	id, err := s.Generate()
+	if err != nil {
+		return UUID{}, err
+	}
+	var u UUID
+	if _, err := rand.Read(u[8:]); err != nil {
+		return UUID{}, fmt.Errorf("read random bytes: %w", err)
+	}
+	v := uint64(id)
+	for i := 0; i < 6; i++ {
+		u[i] = byte(v >> (56 - 8*i))
+	}
+	u[6] = 0x40 | byte(v>>12)&0x0f
+	u[7] = byte(v >> 4)
+	u[8] = 0x80 | u[8]&0x30 | byte(v)&0x0f
+	return u, nil
+}
+
+// String 返回 8-4-4-4-12 格式的小写 UUID 字符串
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
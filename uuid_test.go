@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"regexp"
+	"testing"
+)
+
+// idFromV4 按照 GenerateUUID 的布局取出 UUID 中嵌入的 ID
+func idFromV4(u UUID) int64 {
+	var v uint64
+	for i := 0; i < 6; i++ {
+		v = v<<8 | uint64(u[i])
+	}
+	v = v<<4 | uint64(u[6]&0x0f)
+	v = v<<8 | uint64(u[7])
+	v = v<<4 | uint64(u[8]&0x0f)
+	return int64(v)
+}
+
+func TestGenerateUUID(t *testing.T) {
+	s, err := NewSnowflake(7, 3, WithClock(newFakeClock(testStart)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.GenerateUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := u[6] >> 4; v != 4 {
+		t.Errorf("version = %d, want 4", v)
+	}
+	if variant := u[8] >> 6; variant != 0b10 {
+		t.Errorf("variant bits = %02b, want 10", variant)
+	}
+	if got, want := idFromV4(u), prev+1; got != want {
+		t.Errorf("embedded ID = %d, want %d", got, want)
+	}
+	if str := u.String(); !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(str) {
+		t.Errorf("String() = %q, want a lowercase UUIDv4", str)
+	}
+}
@@ -0,0 +1,56 @@
+package snowflake
+
+import "fmt"
+
+// UUIDv7 将 ID 无损地转换为 UUIDv7，ID 按照默认的 Epoch 和位布局解析
+//
+// 布局遵循 RFC 9562 的 UUIDv7：
+//
+//	字节 0-5   48 位 Unix 毫秒时间戳，即 ID 的时间
+//	字节 6-7   版本号 7 | 12 位 rand_a = 数据中心 ID(5) | 机器 ID(5) | 序列号的第 11-10 位
+//	字节 8     变体 10 | 序列号的第 9-4 位
+//	字节 9     序列号的第 3-0 位 | 0000
+//	字节 10-15 全为 0
+//
+// 时间戳之后的字段顺序与 ID 相同，因此 UUID 按字节比较的顺序与 ID 的数值顺序一致。
+// 剩余的随机位固定为 0，IDFromUUID 据此识别由该方案生成的 UUID。id 为负数时结果
+// 没有意义。
+func (id ID) UUIDv7() UUID {
+	var u UUID
+	ms := uint64(id.Time().UnixMilli())
+	for i := 0; i < 6; i++ {
+		u[i] = byte(ms >> (40 - 8*i))
+	}
+	// 数据中心 ID、机器 ID 和序列号共 22 位，高 12 位放入 rand_a，低 10 位放入 rand_b 的开头
+	low := uint64(id) & (1<<TimestampShift - 1)
+	randA := low >> 10
+	u[6] = 0x70 | byte(randA>>8)
+	u[7] = byte(randA)
+	u[8] = 0x80 | byte(low>>4)&0x3f
+	u[9] = byte(low&0x0f) << 4
+	return u
+}
+
+// IDFromUUID 从 ID.UUIDv7 生成的 UUID 中还原 ID
+//
+// 版本号不是 7、变体不是 RFC 9562、固定为 0 的位不为 0，或者时间早于 Epoch、超出
+// 时间戳字段范围时，返回包装了 ErrNotSnowflakeUUID 的错误。
+func IDFromUUID(u UUID) (ID, error) {
+	if u[6]>>4 != 7 || u[8]>>6 != 0b10 {
+		return 0, fmt.Errorf("%w: %s is not an RFC 9562 version 7 UUID", ErrNotSnowflakeUUID, u)
+	}
+	if u[9]&0x0f != 0 || u[10]|u[11]|u[12]|u[13]|u[14]|u[15] != 0 {
+		return 0, fmt.Errorf("%w: %s has non-zero random bits", ErrNotSnowflakeUUID, u)
+	}
+	var ms int64
+	for i := 0; i < 6; i++ {
+		ms = ms<<8 | int64(u[i])
+	}
+	timestamp := ms - Epoch
+	if timestamp < 0 || timestamp > maxDefaultTimestamp {
+		return 0, fmt.Errorf("%w: %s has a timestamp outside the ID range", ErrNotSnowflakeUUID, u)
+	}
+	randA := int64(u[6]&0x0f)<<8 | int64(u[7])
+	low := randA<<10 | int64(u[8]&0x3f)<<4 | int64(u[9]>>4)
+	return ID(timestamp<<TimestampShift | low), nil
+}
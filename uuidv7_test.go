@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUUIDv7Vector(t *testing.T) {
+	// testStart、数据中心 ID 3、机器 ID 7、序列号 1234
+	id := ID((testStart.UnixMilli()-Epoch)<<TimestampShift | 3<<DataCenterShift | 7<<MachineShift | 1234)
+	if id != 311151275540903122 {
+		t.Fatalf("fixture ID = %d", id)
+	}
+	u := id.UUIDv7()
+	if got, want := u.String(), "018cc820-d888-719d-8d20-000000000000"; got != want {
+		t.Errorf("UUIDv7() = %s, want %s", got, want)
+	}
+	back, err := IDFromUUID(u)
+	if err != nil || back != id {
+		t.Errorf("IDFromUUID(%s) = %d, %v, want %d", u, back, err, id)
+	}
+}
+
+func TestUUIDv7RoundTrip(t *testing.T) {
+	clock := newFakeClock(testStart)
+	s, err := NewSnowflake(MaxMachineID, MaxDataCenterID, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prev UUID
+	for i := 0; i < 5000; i++ {
+		id, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		u := ID(id).UUIDv7()
+		if u[6]>>4 != 7 || u[8]>>6 != 0b10 {
+			t.Fatalf("UUIDv7(%d) = %s has the wrong version or variant", id, u)
+		}
+		if ms := time.UnixMilli(int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])); !ms.Equal(ID(id).Time()) {
+			t.Fatalf("UUIDv7(%d) time = %s, want %s", id, ms, ID(id).Time())
+		}
+		if i > 0 && bytes.Compare(prev[:], u[:]) >= 0 {
+			t.Fatalf("UUIDv7(%d) = %s does not sort after %s", id, u, prev)
+		}
+		back, err := IDFromUUID(u)
+		if err != nil || int64(back) != id {
+			t.Fatalf("IDFromUUID(%s) = %d, %v, want %d", u, back, err, id)
+		}
+		prev = u
+	}
+}
+
+func TestIDFromUUIDErrors(t *testing.T) {
+	valid := ID(311151275540903122).UUIDv7()
+	for _, tc := range []struct {
+		name   string
+		mutate func(u *UUID)
+	}{
+		{"version 4", func(u *UUID) { u[6] = 0x40 | u[6]&0x0f }},
+		{"wrong variant", func(u *UUID) { u[8] &^= 0x80 }},
+		{"random bits in byte 9", func(u *UUID) { u[9] |= 0x01 }},
+		{"random bits in the tail", func(u *UUID) { u[15] = 1 }},
+		{"before epoch", func(u *UUID) { copy(u[:6], []byte{0, 0, 0, 0, 0, 1}) }},
+		{"beyond timestamp range", func(u *UUID) { copy(u[:6], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) }},
+	} {
+		u := valid
+		tc.mutate(&u)
+		if _, err := IDFromUUID(u); !errors.Is(err, ErrNotSnowflakeUUID) {
+			t.Errorf("%s: err = %v, want ErrNotSnowflakeUUID", tc.name, err)
+		}
+	}
+	u, err := MustNew(1, 1).GenerateUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := IDFromUUID(u); !errors.Is(err, ErrNotSnowflakeUUID) {
+		t.Errorf("IDFromUUID of a GenerateUUID result: err = %v, want ErrNotSnowflakeUUID", err)
+	}
+}
@@ -0,0 +1,22 @@
+package snowflake
+
+import "time"
+
+// DefaultFutureTolerance 是 IsValid 允许 ID 时间领先于当前时间的最大幅度
+const DefaultFutureTolerance = time.Minute
+
+// IsValid 判断 id 是否可能是默认配置生成的 Snowflake ID，等价于
+// IsValidWithTolerance(id, DefaultFutureTolerance)
+func IsValid(id int64) bool {
+	return IsValidWithTolerance(id, DefaultFutureTolerance)
+}
+
+// IsValidWithTolerance 判断 id 是否可能是默认配置生成的 Snowflake ID
+//
+// 默认布局的每个位组合都是合法的字段值，正数 ID 嵌入的时间也总是不早于 Epoch，因此
+// 唯一能检查的是 id 必须为正数，并且嵌入的时间不能比当前时间晚 tolerance 以上（容忍
+// 不同节点之间的时钟偏差）。该检查只能过滤明显不合法的值，例如来自其他系统的随机数或
+// 时间错位的 ID，不能证明 ID 确实由某个生成器产生。
+func IsValidWithTolerance(id int64, tolerance time.Duration) bool {
+	return id > 0 && !Parse(id).Timestamp.After(time.Now().Add(tolerance))
+}
@@ -0,0 +1,40 @@
+package snowflake
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIsValid(t *testing.T) {
+	s, err := NewSnowflake(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	future := (time.Now().Add(time.Hour).UnixMilli() - Epoch) << TimestampShift
+	nearFuture := (time.Now().Add(30*time.Second).UnixMilli() - Epoch) << TimestampShift
+	for _, tc := range []struct {
+		name string
+		id   int64
+		want bool
+	}{
+		{"generated", id, true},
+		{"zero", 0, false},
+		{"negative", -id, false},
+		{"max int64", math.MaxInt64, false},
+		{"an hour ahead", future, false},
+		{"within tolerance", nearFuture, true},
+		{"first ID after epoch", 1, true},
+	} {
+		if got := IsValid(tc.id); got != tc.want {
+			t.Errorf("%s: IsValid(%d) = %v, want %v", tc.name, tc.id, got, tc.want)
+		}
+	}
+	if IsValidWithTolerance(nearFuture, time.Second) {
+		t.Error("IsValidWithTolerance accepted an ID 30s ahead with 1s tolerance")
+	}
+}
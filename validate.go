@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Validate 检查实例各个字段的掩码互不重叠，并且恰好覆盖 ID 的全部 63 位
+//
+// 启用 WithUnsigned 时应当覆盖 64 位，NewShortSnowflake 创建的实例应当覆盖 53 位。
+// NewSnowflake 在返回实例之前已经调用过一次，调用方也可以在启动测试中显式调用。字段
+// 重叠时返回的错误会指出是哪两个字段。
+func (s *Snowflake) Validate() error {
+	l := s.layout
+	type field struct {
+		name string
+		mask uint64
+	}
+	fields := []field{
+		{"timestamp", uint64(s.maxTimestamp()) << l.timestampShift()},
+		{"data center ID", uint64(l.maxDataCenterID()) << l.dataCenterShift()},
+		{"machine ID", uint64(l.maxMachineID()) << l.machineShift()},
+		{"tag", uint64(s.maxTag()) << s.tagShift()},
+		{"sequence", uint64(s.maxSequence())},
+	}
+	if s.sonyflake {
+		fields[2].mask = uint64(l.maxMachineID())
+		fields[4].mask = uint64(s.maxSequence()) << l.MachineBits
+	}
+
+	var union uint64
+	for i, f := range fields {
+		for _, g := range fields[:i] {
+			if f.mask&g.mask != 0 {
+				return fmt.Errorf("invalid layout: %s field %#x overlaps %s field %#x", f.name, f.mask, g.name, g.mask)
+			}
+		}
+		union |= f.mask
+	}
+
+	want := s.idBits
+	if got := bits.OnesCount64(union); got != want || bits.Len64(union) != want {
+		return fmt.Errorf("invalid layout: fields cover %d bits (mask %#x), want the low %d bits", got, union, want)
+	}
+	return nil
+}
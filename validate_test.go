@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		new  func() (*Snowflake, error)
+	}{
+		{"default", func() (*Snowflake, error) { return NewSnowflake(1, 1) }},
+		{"tagged", func() (*Snowflake, error) { return NewSnowflake(1, 1, WithTagBits(11)) }},
+		{"unsigned", func() (*Snowflake, error) { return NewSnowflake(1, 1, WithUnsigned()) }},
+		{"layout", func() (*Snowflake, error) {
+			return NewSnowflakeWithLayout(Layout{MachineBits: 8, SequenceBits: 14}, 200, 0, WithTagBits(4))
+		}},
+		{"short", func() (*Snowflake, error) { return NewShortSnowflake(15) }},
+		{"sonyflake", func() (*Snowflake, error) { return NewSonyflakeCompat(1) }},
+	} {
+		s, err := tc.new()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if err := s.Validate(); err != nil {
+			t.Errorf("%s: Validate() = %v", tc.name, err)
+		}
+	}
+}
+
+func TestValidateRejectsBrokenLayout(t *testing.T) {
+	// 直接修改内部字段，模拟选项组合出错时的位布局
+	s := MustNew(1, 1)
+	s.idBits = 65
+	if err := s.Validate(); err == nil || !strings.Contains(err.Error(), "cover 64 bits") {
+		t.Errorf("Validate with a timestamp beyond bit 63: err = %v", err)
+	}
+
+	s = MustNew(1, 1)
+	s.sonyflake, s.tagBits = true, 2
+	if err := s.Validate(); err == nil || !strings.Contains(err.Error(), "overlaps") {
+		t.Errorf("Validate with a tag inside the sonyflake sequence: err = %v", err)
+	}
+}
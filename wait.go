@@ -0,0 +1,76 @@
+package snowflake
+
+import (
+	"context"
+	"time"
+)
+
+// WaitStrategy 决定当前毫秒的序列号用尽时如何等待下一毫秒
+type WaitStrategy int
+
+const (
+	// WaitSpin 在持有锁的情况下循环读取时钟，延迟最低但会占满一个 CPU 核心，
+	// 适合对延迟极其敏感的调用方
+	WaitSpin WaitStrategy = iota
+	// WaitSleep 计算到下一毫秒的剩余时间并休眠，醒来后重新检查时钟，
+	// 不会空转 CPU，但受调度精度影响延迟略高（默认）
+	WaitSleep
+)
+
+// WithWaitStrategy 设置序列号用尽时的等待方式，默认为 WaitSleep
+func WithWaitStrategy(w WaitStrategy) Option {
+	return func(s *Snowflake) {
+		s.waitStrategy = w
+	}
+}
+
+// waitNextTick 等待时钟越过 s.lastTimestamp，调用方必须持有 s.mu
+//
+// 休眠时只休眠一次，调用方需要重新读取时钟确认时间已经前进。ctx 可以被取消时
+// 总是使用定时器休眠，以便及时响应取消并返回 ctx.Err()；通过 WithClock 注入的时间源
+// 只在 Sleep 时前进，因此也总是休眠。
+func (s *Snowflake) waitNextTick(ctx context.Context) error {
+	if s.waitStrategy == WaitSleep || ctx.Done() != nil || !systemSleep(s.clock) {
+		return s.sleepContext(ctx, s.untilNextTick())
+	}
+	for s.tick() <= s.lastTimestamp {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sleepContext 通过实例的时间源休眠 d，期间 ctx 被取消时提前返回 ctx.Err()
+//
+// 使用系统时钟休眠时可以被 ctx 中断，通过 WithClock 注入的时间源则在休眠结束后
+// 再检查 ctx。
+func (s *Snowflake) sleepContext(ctx context.Context, d time.Duration) error {
+	if !systemSleep(s.clock) {
+		s.clock.Sleep(d)
+		return ctx.Err()
+	}
+	if ctx.Done() == nil {
+		time.Sleep(d)
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// systemSleep 判断时间源的 Sleep 是否直接使用系统时钟
+func systemSleep(c Clock) bool {
+	switch c := c.(type) {
+	case realClock, funcClock:
+		return true
+	case *monotonicClock:
+		return systemSleep(c.base)
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package snowflake
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// frozenTimeFunc 返回一个在 release 之前停在 start 的时间函数，以及读取次数的计数器
+func frozenTimeFunc(start int64, release time.Duration) (func() int64, *atomic.Int64) {
+	var reads atomic.Int64
+	var released atomic.Bool
+	time.AfterFunc(release, func() { released.Store(true) })
+	return func() int64 {
+		reads.Add(1)
+		if released.Load() {
+			return start + 1
+		}
+		return start
+	}, &reads
+}
+
+func TestWaitStrategies(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		strategy WaitStrategy
+		maxReads int64 // 等待期间时钟读取次数的上限，0 表示不限制
+	}{
+		{"sleep", WaitSleep, 1000},
+		{"spin", WaitSpin, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			start := testStart.UnixMilli()
+			now, reads := frozenTimeFunc(start, 20*time.Millisecond)
+			s, err := NewSnowflake(1, 1, WithTimeFunc(now), WithWaitStrategy(tc.strategy), WithSequenceBits(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := s.Generate(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			reads.Store(0)
+			c := s.Parse(generateWithin(t, s, 2*time.Second))
+			if c.Timestamp.UnixMilli() != start+1 || c.Sequence != 0 {
+				t.Errorf("ID after exhaustion = %+v, want the next millisecond with sequence 0", c)
+			}
+			if n := reads.Load(); tc.maxReads > 0 && n > tc.maxReads {
+				t.Errorf("clock read %d times while sleeping, want at most %d", n, tc.maxReads)
+			}
+		})
+	}
+}
+
+func TestDefaultWaitSleeps(t *testing.T) {
+	start := testStart.UnixMilli()
+	now, reads := frozenTimeFunc(start, 20*time.Millisecond)
+	s, err := NewSnowflake(1, 1, WithTimeFunc(now), WithSequenceBits(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.waitStrategy != WaitSleep {
+		t.Errorf("default wait strategy = %d, want WaitSleep", s.waitStrategy)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := s.Generate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reads.Store(0)
+	generateWithin(t, s, 2*time.Second)
+	// 空转会在 20ms 内读取成千上万次时钟
+	if n := reads.Load(); n > 1000 {
+		t.Errorf("clock read %d times while waiting with the default strategy, want it to sleep", n)
+	}
+}
+
+// BenchmarkWaitStrategies 在序列号只有 4 位、每毫秒很快用尽的情况下比较两种等待策略
+func BenchmarkWaitStrategies(b *testing.B) {
+	for _, bc := range []struct {
+		name     string
+		strategy WaitStrategy
+	}{
+		{"WaitSpin", WaitSpin},
+		{"WaitSleep", WaitSleep},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			s, err := NewSnowflake(1, 1, WithWaitStrategy(bc.strategy), WithSequenceBits(4))
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Generate(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
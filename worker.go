@@ -0,0 +1,26 @@
+package snowflake
+
+// MaxWorkerID 是 10 位 worker ID 的最大值，即数据中心 ID 和机器 ID 合并后的范围
+const MaxWorkerID = MaxDataCenterID<<MachineBits | MaxMachineID
+
+// NewSnowflakeWorker 使用原始 Twitter Snowflake 的 10 位 worker ID 创建实例
+//
+// workerID 的高 5 位作为数据中心 ID，低 5 位作为机器 ID，生成的 ID 与分别传入两者时
+// 完全相同，可以通过 Components.WorkerID 重新合并。workerID 必须在 [0, MaxWorkerID]
+// 范围内，否则返回包装了 ErrWorkerIDRange 的 *RangeError。
+func NewSnowflakeWorker(workerID int64, opts ...Option) (*Snowflake, error) {
+	if workerID < 0 || workerID > MaxWorkerID {
+		return nil, &RangeError{Err: ErrWorkerIDRange, Value: workerID, Max: MaxWorkerID}
+	}
+	return NewSnowflake(workerID&MaxMachineID, workerID>>MachineBits, opts...)
+}
+
+// WorkerID 将数据中心 ID 和机器 ID 合并为默认位布局下的 10 位 worker ID
+func (c Components) WorkerID() int64 {
+	return c.DataCenterID<<MachineBits | c.MachineID
+}
+
+// WorkerID 返回 ID 中 10 位的 worker ID，即数据中心 ID 和机器 ID 合并后的值
+func (id ID) WorkerID() int64 {
+	return (int64(id) >> MachineShift) & MaxWorkerID
+}
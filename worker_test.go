@@ -0,0 +1,47 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewSnowflakeWorker(t *testing.T) {
+	for _, workerID := range []int64{0, 1, 37, MaxWorkerID} {
+		clock := newFakeClock(testStart)
+		w, err := NewSnowflakeWorker(workerID, WithClock(clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := NewSnowflake(workerID&MaxMachineID, workerID>>MachineBits, WithClock(newFakeClock(testStart)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := w.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("worker %d: ID = %d, want %d from the split IDs", workerID, got, want)
+		}
+		if c := Parse(got).WorkerID(); c != workerID {
+			t.Errorf("worker %d: Components.WorkerID() = %d", workerID, c)
+		}
+		if id := ID(got).WorkerID(); id != workerID {
+			t.Errorf("worker %d: ID.WorkerID() = %d", workerID, id)
+		}
+	}
+}
+
+func TestNewSnowflakeWorkerRange(t *testing.T) {
+	for _, workerID := range []int64{-1, MaxWorkerID + 1} {
+		_, err := NewSnowflakeWorker(workerID)
+		var re *RangeError
+		if !errors.Is(err, ErrWorkerIDRange) || !errors.As(err, &re) || re.Value != workerID || re.Max != 1023 {
+			t.Errorf("NewSnowflakeWorker(%d): err = %v, want a RangeError wrapping ErrWorkerIDRange", workerID, err)
+		}
+	}
+}